@@ -0,0 +1,56 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// AvailLengthToFeeMethod is the runtime API pricing the length of an
+// extrinsic.
+const AvailLengthToFeeMethod = "TransactionPaymentApi_query_length_to_fee"
+
+// availSignedExtrinsicOverhead bounds the size of a signed submit_data
+// extrinsic besides its data: version, signer, signature, era, nonce, tip,
+// app ID and call index.
+const availSignedExtrinsicOverhead = 1 + 33 + 65 + 2 + 4 + 1 + 4 + 2
+
+var ErrInvalidAvailFee = errors.New("invalid Avail fee encoding")
+
+// AvailDataExtrinsicSize returns the size of a signed submit_data extrinsic
+// carrying [size] bytes.
+func AvailDataExtrinsicSize(size int) int {
+	body := availSignedExtrinsicOverhead + compactSize(uint64(size)) + size
+	return compactSize(uint64(body)) + body
+}
+
+// EstimateAvailFee returns the length fee, in plancks, of a submit_data
+// extrinsic carrying [size] bytes, as priced by the node at [networkURL].
+// The weight fee of the call is not included.
+func EstimateAvailFee(ctx context.Context, networkURL string, size int) (*big.Int, error) {
+	client, err := rpc.DialContext(ctx, networkURL)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	length := binary.LittleEndian.AppendUint32(nil, uint32(AvailDataExtrinsicSize(size)))
+	var encoded hexutil.Bytes
+	if err := client.CallContext(ctx, &encoded, "state_call", AvailLengthToFeeMethod, hexutil.Bytes(length)); err != nil {
+		return nil, err
+	}
+	// The fee is a little endian u128.
+	if len(encoded) != 16 {
+		return nil, ErrInvalidAvailFee
+	}
+	slices.Reverse(encoded)
+	return new(big.Int).SetBytes(encoded), nil
+}
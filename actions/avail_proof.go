@@ -0,0 +1,85 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrAvailLeafMismatch     = errors.New("data does not match proof leaf")
+	ErrAvailInvalidProof     = errors.New("invalid data proof")
+	ErrAvailDataRootMismatch = errors.New("data root does not match block header")
+)
+
+// AvailDataRoots are the roots committed to by the data root of an Avail
+// block header.
+type AvailDataRoots struct {
+	DataRoot   common.Hash `json:"dataRoot"`
+	BlobRoot   common.Hash `json:"blobRoot"`
+	BridgeRoot common.Hash `json:"bridgeRoot"`
+}
+
+// AvailDataProof is the Merkle proof returned by kate_queryDataProof that a
+// submitted data leaf is part of the blob root of its block.
+type AvailDataProof struct {
+	Roots          AvailDataRoots `json:"roots"`
+	Proof          []common.Hash  `json:"proof"`
+	NumberOfLeaves uint32         `json:"numberOfLeaves"`
+	LeafIndex      uint32         `json:"leafIndex"`
+	Leaf           common.Hash    `json:"leaf"`
+}
+
+// VerifyAvailDataProof checks that [data] is the leaf of [proof] and that
+// the leaf is committed to by [headerDataRoot], the data root found in the
+// block header.
+//
+// The blob root is a binary Merkle tree over keccak256 leaves, where a node
+// without sibling is promoted to the next level unchanged, and the data root
+// is keccak256(blobRoot || bridgeRoot).
+func VerifyAvailDataProof(proof *AvailDataProof, data []byte, headerDataRoot common.Hash) error {
+	if crypto.Keccak256Hash(data) != proof.Leaf {
+		return ErrAvailLeafMismatch
+	}
+	if proof.NumberOfLeaves == 0 || proof.LeafIndex >= proof.NumberOfLeaves {
+		return ErrAvailInvalidProof
+	}
+
+	var (
+		computed = proof.Leaf
+		position = proof.LeafIndex
+		width    = proof.NumberOfLeaves
+		siblings = proof.Proof
+	)
+	for width > 1 {
+		switch {
+		case position%2 == 1:
+			if len(siblings) == 0 {
+				return ErrAvailInvalidProof
+			}
+			computed = crypto.Keccak256Hash(siblings[0][:], computed[:])
+			siblings = siblings[1:]
+		case position+1 < width:
+			if len(siblings) == 0 {
+				return ErrAvailInvalidProof
+			}
+			computed = crypto.Keccak256Hash(computed[:], siblings[0][:])
+			siblings = siblings[1:]
+		}
+		position /= 2
+		width = (width + 1) / 2
+	}
+	if len(siblings) != 0 || computed != proof.Roots.BlobRoot {
+		return ErrAvailInvalidProof
+	}
+
+	dataRoot := crypto.Keccak256Hash(proof.Roots.BlobRoot[:], proof.Roots.BridgeRoot[:])
+	if dataRoot != proof.Roots.DataRoot || dataRoot != headerDataRoot {
+		return ErrAvailDataRootMismatch
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// newAvailDataProof builds the blob root over [leaves] and the proof of the
+// leaf at [index], promoting nodes without sibling.
+func newAvailDataProof(leaves [][]byte, index uint32) *AvailDataProof {
+	level := make([]common.Hash, len(leaves))
+	for i, leaf := range leaves {
+		level[i] = crypto.Keccak256Hash(leaf)
+	}
+	proof := &AvailDataProof{
+		NumberOfLeaves: uint32(len(leaves)),
+		LeafIndex:      index,
+		Leaf:           level[index],
+	}
+	position := index
+	for len(level) > 1 {
+		if sibling := position ^ 1; sibling < uint32(len(level)) {
+			proof.Proof = append(proof.Proof, level[sibling])
+		}
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, crypto.Keccak256Hash(level[i][:], level[i+1][:]))
+		}
+		level = next
+		position /= 2
+	}
+	proof.Roots.BlobRoot = level[0]
+	proof.Roots.BridgeRoot = common.Hash{0x01}
+	proof.Roots.DataRoot = crypto.Keccak256Hash(proof.Roots.BlobRoot[:], proof.Roots.BridgeRoot[:])
+	return proof
+}
+
+func TestVerifyAvailDataProof(t *testing.T) {
+	leaves := [][]byte{{0}, {1}, {2}, {3}, {4}}
+	for index := range leaves {
+		proof := newAvailDataProof(leaves, uint32(index))
+		require.NoError(t, VerifyAvailDataProof(proof, leaves[index], proof.Roots.DataRoot))
+	}
+
+	proof := newAvailDataProof(leaves, 2)
+	require.ErrorIs(t, VerifyAvailDataProof(proof, []byte{3}, proof.Roots.DataRoot), ErrAvailLeafMismatch)
+	require.ErrorIs(t, VerifyAvailDataProof(proof, leaves[2], common.Hash{}), ErrAvailDataRootMismatch)
+
+	tampered := newAvailDataProof(leaves, 2)
+	tampered.Proof[0] = common.Hash{}
+	require.ErrorIs(t, VerifyAvailDataProof(tampered, leaves[2], tampered.Roots.DataRoot), ErrAvailInvalidProof)
+
+	truncated := newAvailDataProof(leaves, 2)
+	truncated.Proof = truncated.Proof[:1]
+	require.ErrorIs(t, VerifyAvailDataProof(truncated, leaves[2], truncated.Roots.DataRoot), ErrAvailInvalidProof)
+
+	single := newAvailDataProof(leaves[:1], 0)
+	require.Empty(t, single.Proof)
+	require.NoError(t, VerifyAvailDataProof(single, leaves[0], single.Roots.DataRoot))
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var ErrInvalidSCALEBytes = errors.New("invalid SCALE encoded bytes")
+
+// scaleBytes SCALE encodes [b] as a Vec<u8>: a compact length prefix followed
+// by the bytes.
+func scaleBytes(b []byte) []byte {
+	n := uint64(len(b))
+	var prefix []byte
+	switch {
+	case n < 1<<6:
+		prefix = []byte{byte(n << 2)}
+	case n < 1<<14:
+		prefix = []byte{byte(n<<2) | 0b01, byte(n >> 6)}
+	case n < 1<<30:
+		prefix = []byte{byte(n<<2) | 0b10, byte(n >> 6), byte(n >> 14), byte(n >> 22)}
+	default:
+		// Big integer mode, the upper 6 bits hold the byte length minus 4.
+		prefix = []byte{0b11 | (8-4)<<2}
+		for i := 0; i < 8; i++ {
+			prefix = append(prefix, byte(n>>(8*i)))
+		}
+	}
+	return append(prefix, b...)
+}
+
+// compactSize returns the size of the SCALE compact encoding of [n].
+func compactSize(n uint64) int {
+	switch {
+	case n < 1<<6:
+		return 1
+	case n < 1<<14:
+		return 2
+	case n < 1<<30:
+		return 4
+	default:
+		return 1 + 8
+	}
+}
+
+// decodeScaleBytes decodes a Vec<u8> encoded by [scaleBytes]. [b] must not
+// hold anything after the vector.
+func decodeScaleBytes(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, ErrInvalidSCALEBytes
+	}
+	var (
+		n          uint64
+		prefixSize int
+	)
+	switch b[0] & 0b11 {
+	case 0b00:
+		n, prefixSize = uint64(b[0]>>2), 1
+	case 0b01:
+		if len(b) < 2 {
+			return nil, ErrInvalidSCALEBytes
+		}
+		n, prefixSize = uint64(binary.LittleEndian.Uint16(b))>>2, 2
+	case 0b10:
+		if len(b) < 4 {
+			return nil, ErrInvalidSCALEBytes
+		}
+		n, prefixSize = uint64(binary.LittleEndian.Uint32(b))>>2, 4
+	default:
+		size := int(b[0]>>2) + 4
+		if size > 8 || len(b) < 1+size {
+			return nil, ErrInvalidSCALEBytes
+		}
+		for i := size; i > 0; i-- {
+			n = n<<8 | uint64(b[i])
+		}
+		prefixSize = 1 + size
+	}
+	if uint64(len(b)-prefixSize) != n {
+		return nil, ErrInvalidSCALEBytes
+	}
+	return b[prefixSize:], nil
+}
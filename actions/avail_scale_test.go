@@ -0,0 +1,44 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaleBytes(t *testing.T) {
+	tests := []struct {
+		size   int
+		prefix []byte
+	}{
+		{size: 0, prefix: []byte{0x00}},
+		{size: 1, prefix: []byte{0x04}},
+		{size: 63, prefix: []byte{0xfc}},
+		{size: 64, prefix: []byte{0x01, 0x01}},
+		{size: 16383, prefix: []byte{0xfd, 0xff}},
+		{size: 16384, prefix: []byte{0x02, 0x00, 0x01, 0x00}},
+	}
+	for _, tt := range tests {
+		data := make([]byte, tt.size)
+		encoded := scaleBytes(data)
+		require.Equal(t, tt.prefix, encoded[:len(tt.prefix)])
+		require.Len(t, encoded, len(tt.prefix)+tt.size)
+
+		decoded, err := decodeScaleBytes(encoded)
+		require.NoError(t, err)
+		require.Equal(t, data, decoded)
+	}
+
+	// Big integer mode with a 4-byte length.
+	decoded, err := decodeScaleBytes([]byte{0x03, 0x01, 0x00, 0x00, 0x00, 0xAA})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xAA}, decoded)
+
+	_, err = decodeScaleBytes(nil)
+	require.ErrorIs(t, err, ErrInvalidSCALEBytes)
+	_, err = decodeScaleBytes(append(scaleBytes([]byte{1}), 2))
+	require.ErrorIs(t, err, ErrInvalidSCALEBytes)
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// Blob encoding
+//
+// A payload is prefixed with its length as a big-endian uint64 and split into
+// 31-byte words. Each word is stored in the low 31 bytes of a 32-byte field
+// element whose first byte is zero, so every field element is below the BLS
+// modulus. Field elements fill the blobs in order, the last one is zero
+// padded.
+
+const (
+	// FieldElementsPerBlob is the number of field elements in a blob.
+	FieldElementsPerBlob = 4096
+	// BytesPerFieldElement is the size of a serialized field element.
+	BytesPerFieldElement = 32
+	// UsableBytesPerFieldElement is the number of payload bytes a field
+	// element carries.
+	UsableBytesPerFieldElement = BytesPerFieldElement - 1
+	// UsableBytesPerBlob is the number of payload bytes a blob carries.
+	UsableBytesPerBlob = FieldElementsPerBlob * UsableBytesPerFieldElement
+	// MaxBlobsPerTx is the maximum number of blobs in a transaction.
+	MaxBlobsPerTx = 6
+
+	blobLengthHeaderSize = 8
+)
+
+var (
+	ErrInvalidFieldElement = errors.New("invalid field element")
+	ErrInvalidBlobLength   = errors.New("invalid blob length header")
+	ErrInvalidBlobPadding  = errors.New("invalid blob padding")
+)
+
+// EncodeBlobs encodes [data] into as many blobs as needed. It always returns
+// at least one blob.
+func EncodeBlobs(data []byte) []kzg4844.Blob {
+	payload := make([]byte, blobLengthHeaderSize, blobLengthHeaderSize+len(data))
+	binary.BigEndian.PutUint64(payload, uint64(len(data)))
+	payload = append(payload, data...)
+
+	blobs := make([]kzg4844.Blob, BlobCount(len(data)))
+	for i := range blobs {
+		for j := 0; j < FieldElementsPerBlob && len(payload) > 0; j++ {
+			offset := j*BytesPerFieldElement + 1
+			n := copy(blobs[i][offset:offset+UsableBytesPerFieldElement], payload)
+			payload = payload[n:]
+		}
+	}
+	return blobs
+}
+
+// BlobCount returns the number of blobs [EncodeBlobs] encodes [size] bytes
+// into.
+func BlobCount(size int) int {
+	return (blobLengthHeaderSize + size + UsableBytesPerBlob - 1) / UsableBytesPerBlob
+}
+
+// DecodeBlobs returns the payload encoded in [blobs] by [EncodeBlobs].
+func DecodeBlobs(blobs []kzg4844.Blob) ([]byte, error) {
+	payload := make([]byte, 0, len(blobs)*UsableBytesPerBlob)
+	for i := range blobs {
+		for j := 0; j < FieldElementsPerBlob; j++ {
+			offset := j * BytesPerFieldElement
+			if blobs[i][offset] != 0 {
+				return nil, ErrInvalidFieldElement
+			}
+			payload = append(payload, blobs[i][offset+1:offset+BytesPerFieldElement]...)
+		}
+	}
+	if len(payload) < blobLengthHeaderSize {
+		return nil, ErrInvalidBlobLength
+	}
+	size := binary.BigEndian.Uint64(payload)
+	payload = payload[blobLengthHeaderSize:]
+	if size > uint64(len(payload)) {
+		return nil, ErrInvalidBlobLength
+	}
+	// Reject trailing blobs that carry no data, the encoding is canonical.
+	if uint64(len(payload))-size >= UsableBytesPerBlob {
+		return nil, ErrInvalidBlobLength
+	}
+	for _, b := range payload[size:] {
+		if b != 0 {
+			return nil, ErrInvalidBlobPadding
+		}
+	}
+	return payload[:size], nil
+}
+
+// SplitBlobs groups [blobs] into batches of at most [MaxBlobsPerTx], one per
+// transaction.
+func SplitBlobs(blobs []kzg4844.Blob) [][]kzg4844.Blob {
+	batches := make([][]kzg4844.Blob, 0, (len(blobs)+MaxBlobsPerTx-1)/MaxBlobsPerTx)
+	for len(blobs) > 0 {
+		n := min(len(blobs), MaxBlobsPerTx)
+		batches = append(batches, blobs[:n])
+		blobs = blobs[n:]
+	}
+	return batches
+}
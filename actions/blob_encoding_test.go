@@ -0,0 +1,147 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobEncodingRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  int
+		blobs int
+	}{
+		{
+			name:  "Empty",
+			size:  0,
+			blobs: 1,
+		},
+		{
+			name:  "Small",
+			size:  100,
+			blobs: 1,
+		},
+		{
+			name:  "FullBlob",
+			size:  UsableBytesPerBlob - blobLengthHeaderSize,
+			blobs: 1,
+		},
+		{
+			name:  "TwoBlobs",
+			size:  UsableBytesPerBlob - blobLengthHeaderSize + 1,
+			blobs: 2,
+		},
+		{
+			name:  "MultipleTransactions",
+			size:  MaxBlobsPerTx*UsableBytesPerBlob + 1,
+			blobs: MaxBlobsPerTx + 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			data := make([]byte, tt.size)
+			_, err := rand.Read(data)
+			require.NoError(err)
+
+			blobs := EncodeBlobs(data)
+			require.Len(blobs, tt.blobs)
+			require.Equal(tt.blobs, BlobCount(tt.size))
+			for _, blob := range blobs {
+				for i := 0; i < FieldElementsPerBlob; i++ {
+					require.Zero(blob[i*BytesPerFieldElement])
+				}
+			}
+
+			decoded, err := DecodeBlobs(blobs)
+			require.NoError(err)
+			require.Equal(data, decoded)
+		})
+	}
+}
+
+func TestBlobEncodingCommitment(t *testing.T) {
+	require := require.New(t)
+
+	// A payload of 0xff words would not be a valid blob without encoding.
+	data := make([]byte, UsableBytesPerBlob-blobLengthHeaderSize)
+	for i := range data {
+		data[i] = 0xff
+	}
+	blobs := EncodeBlobs(data)
+	require.Len(blobs, 1)
+	_, err := kzg4844.BlobToCommitment(blobs[0])
+	require.NoError(err)
+}
+
+func TestDecodeBlobsErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		blobs       func() []kzg4844.Blob
+		expectedErr error
+	}{
+		{
+			name:        "NoBlobs",
+			blobs:       func() []kzg4844.Blob { return nil },
+			expectedErr: ErrInvalidBlobLength,
+		},
+		{
+			name: "InvalidFieldElement",
+			blobs: func() []kzg4844.Blob {
+				blobs := EncodeBlobs([]byte{1})
+				blobs[0][BytesPerFieldElement] = 0xff
+				return blobs
+			},
+			expectedErr: ErrInvalidFieldElement,
+		},
+		{
+			name: "LengthTooLarge",
+			blobs: func() []kzg4844.Blob {
+				blobs := EncodeBlobs([]byte{1})
+				blobs[0][1] = 0xff
+				return blobs
+			},
+			expectedErr: ErrInvalidBlobLength,
+		},
+		{
+			name: "TrailingBlob",
+			blobs: func() []kzg4844.Blob {
+				return append(EncodeBlobs([]byte{1}), kzg4844.Blob{})
+			},
+			expectedErr: ErrInvalidBlobLength,
+		},
+		{
+			name: "InvalidPadding",
+			blobs: func() []kzg4844.Blob {
+				blobs := EncodeBlobs([]byte{1})
+				blobs[0][BytesPerFieldElement-1] = 0xff
+				return blobs
+			},
+			expectedErr: ErrInvalidBlobPadding,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeBlobs(tt.blobs())
+			require.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestSplitBlobs(t *testing.T) {
+	require := require.New(t)
+
+	blobs := make([]kzg4844.Blob, 2*MaxBlobsPerTx+1)
+	batches := SplitBlobs(blobs)
+	require.Len(batches, 3)
+	require.Len(batches[0], MaxBlobsPerTx)
+	require.Len(batches[1], MaxBlobsPerTx)
+	require.Len(batches[2], 1)
+}
@@ -0,0 +1,180 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	ErrBlobsNotSupported = errors.New("latest header has no excess blob gas")
+	ErrMaxFeeExceeded    = errors.New("blob transaction not included before reaching the max fee")
+	ErrBlobTxFailed      = errors.New("blob transaction failed")
+	ErrBlobTxReorged     = errors.New("blob transaction reorged out")
+	ErrBlobHashMismatch  = errors.New("included transaction does not carry the submitted blobs")
+	ErrInvalidFeeConfig  = errors.New("invalid blob fee config")
+)
+
+// BlobFeeConfig controls how [SendBlobAction] prices its transactions and
+// replaces them while they are not included. Fields left to 0 take their
+// value from [NewDefaultBlobFeeConfig].
+type BlobFeeConfig struct {
+	// Multiplier is applied to the current base fee and blob base fee to
+	// compute the fee caps of the first submission.
+	Multiplier float64 `json:"multiplier"`
+	// BumpPercent is the increase of the tip, fee cap and blob fee cap of a
+	// replacement. Geth's blob pool requires at least 100.
+	BumpPercent uint64 `json:"bump_percent"`
+	// MaxFeeCap and MaxBlobFeeCap, in wei, bound the fee caps. No
+	// transaction is sent past either of them.
+	MaxFeeCap     uint64 `json:"max_fee_cap"`
+	MaxBlobFeeCap uint64 `json:"max_blob_fee_cap"`
+	// ResubmitInterval is how long a transaction may stay pending before it
+	// is replaced.
+	ResubmitInterval time.Duration `json:"resubmit_interval"`
+	// PollInterval is how often the receipt is polled.
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+func NewDefaultBlobFeeConfig() BlobFeeConfig {
+	return BlobFeeConfig{
+		Multiplier:       2,
+		BumpPercent:      100,
+		MaxFeeCap:        500_000_000_000, // 500 gwei
+		MaxBlobFeeCap:    500_000_000_000, // 500 gwei
+		ResubmitInterval: 36 * time.Second,
+		PollInterval:     3 * time.Second,
+	}
+}
+
+// withDefaults returns [c] with its unset fields taken from
+// [NewDefaultBlobFeeConfig]. Negative values are rejected.
+func (c BlobFeeConfig) withDefaults() (BlobFeeConfig, error) {
+	defaults := NewDefaultBlobFeeConfig()
+	if c.Multiplier < 0 || math.IsNaN(c.Multiplier) || math.IsInf(c.Multiplier, 0) {
+		return BlobFeeConfig{}, fmt.Errorf("%w: multiplier %g", ErrInvalidFeeConfig, c.Multiplier)
+	}
+	if c.ResubmitInterval < 0 {
+		return BlobFeeConfig{}, fmt.Errorf("%w: resubmit interval %s", ErrInvalidFeeConfig, c.ResubmitInterval)
+	}
+	if c.PollInterval < 0 {
+		return BlobFeeConfig{}, fmt.Errorf("%w: poll interval %s", ErrInvalidFeeConfig, c.PollInterval)
+	}
+	if c.Multiplier == 0 {
+		c.Multiplier = defaults.Multiplier
+	}
+	if c.BumpPercent == 0 {
+		c.BumpPercent = defaults.BumpPercent
+	}
+	if c.MaxFeeCap == 0 {
+		c.MaxFeeCap = defaults.MaxFeeCap
+	}
+	if c.MaxBlobFeeCap == 0 {
+		c.MaxBlobFeeCap = defaults.MaxBlobFeeCap
+	}
+	if c.ResubmitInterval == 0 {
+		c.ResubmitInterval = defaults.ResubmitInterval
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = defaults.PollInterval
+	}
+	return c, nil
+}
+
+// blobTxFees holds the prices of a blob transaction, in wei.
+type blobTxFees struct {
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	BlobFeeCap *big.Int
+}
+
+// estimateBlobTxFees prices a blob transaction from the latest header: the
+// base fee and the blob base fee derived from its excess blob gas are scaled
+// by [config.Multiplier].
+func estimateBlobTxFees(ctx context.Context, client *ethclient.Client, config BlobFeeConfig) (*blobTxFees, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.ExcessBlobGas == nil || header.BaseFee == nil {
+		return nil, ErrBlobsNotSupported
+	}
+	gasTipCap, err := client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blobBaseFee := eip4844.CalcBlobFee(*header.ExcessBlobGas)
+	return &blobTxFees{
+		GasTipCap:  gasTipCap,
+		GasFeeCap:  new(big.Int).Add(scaleFee(header.BaseFee, config.Multiplier), gasTipCap),
+		BlobFeeCap: scaleFee(blobBaseFee, config.Multiplier),
+	}, nil
+}
+
+// BlobBaseFee returns the blob base fee, in wei per blob gas, derived from
+// the excess blob gas of the latest header.
+func BlobBaseFee(ctx context.Context, client *ethclient.Client) (*big.Int, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if header.ExcessBlobGas == nil {
+		return nil, ErrBlobsNotSupported
+	}
+	return eip4844.CalcBlobFee(*header.ExcessBlobGas), nil
+}
+
+// EstimateBlobCost returns the fee, in wei, of posting [size] bytes with
+// [SendBlobAction] at the current base fee and blob base fee.
+func EstimateBlobCost(ctx context.Context, client *ethclient.Client, size int) (*big.Int, error) {
+	fees, err := estimateBlobTxFees(ctx, client, BlobFeeConfig{Multiplier: 1})
+	if err != nil {
+		return nil, err
+	}
+	blobs := BlobCount(size)
+	txs := (blobs + MaxBlobsPerTx - 1) / MaxBlobsPerTx
+	blobGas := new(big.Int).SetUint64(uint64(blobs) * params.BlobTxBlobGasPerBlob)
+	gas := new(big.Int).SetUint64(uint64(txs) * params.TxGas)
+	cost := new(big.Int).Mul(blobGas, fees.BlobFeeCap)
+	return cost.Add(cost, gas.Mul(gas, fees.GasFeeCap)), nil
+}
+
+// bump returns the fees of a replacement of a transaction priced with [f].
+func (f *blobTxFees) bump(percent uint64) *blobTxFees {
+	return &blobTxFees{
+		GasTipCap:  bumpFee(f.GasTipCap, percent),
+		GasFeeCap:  bumpFee(f.GasFeeCap, percent),
+		BlobFeeCap: bumpFee(f.BlobFeeCap, percent),
+	}
+}
+
+// exceeds returns true if [f] is above the caps of [config].
+func (f *blobTxFees) exceeds(config BlobFeeConfig) bool {
+	return f.GasFeeCap.Cmp(new(big.Int).SetUint64(config.MaxFeeCap)) > 0 ||
+		f.BlobFeeCap.Cmp(new(big.Int).SetUint64(config.MaxBlobFeeCap)) > 0
+}
+
+func scaleFee(fee *big.Int, multiplier float64) *big.Int {
+	scaled, _ := new(big.Float).Mul(new(big.Float).SetInt(fee), big.NewFloat(multiplier)).Int(nil)
+	return scaled
+}
+
+// bumpFee increases [fee] by [percent], and by at least 1 wei.
+func bumpFee(fee *big.Int, percent uint64) *big.Int {
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+percent))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(fee) <= 0 {
+		bumped.Add(fee, big.NewInt(1))
+	}
+	return bumped
+}
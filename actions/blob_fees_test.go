@@ -0,0 +1,62 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobTxFeesBump(t *testing.T) {
+	require := require.New(t)
+
+	fees := &blobTxFees{
+		GasTipCap:  big.NewInt(0),
+		GasFeeCap:  big.NewInt(1_000),
+		BlobFeeCap: scaleFee(big.NewInt(100), 2),
+	}
+	require.Equal(big.NewInt(200), fees.BlobFeeCap)
+
+	bumped := fees.bump(100)
+	require.Equal(big.NewInt(1), bumped.GasTipCap)
+	require.Equal(big.NewInt(2_000), bumped.GasFeeCap)
+	require.Equal(big.NewInt(400), bumped.BlobFeeCap)
+
+	config := BlobFeeConfig{MaxFeeCap: 2_000, MaxBlobFeeCap: 400}
+	require.False(bumped.exceeds(config))
+	require.True(bumped.bump(100).exceeds(config))
+}
+
+func TestBlobFeeConfigDefaults(t *testing.T) {
+	require := require.New(t)
+
+	defaults := NewDefaultBlobFeeConfig()
+	config, err := BlobFeeConfig{}.withDefaults()
+	require.NoError(err)
+	require.Equal(defaults, config)
+
+	// Set fields are kept, the others are defaulted one by one.
+	config, err = BlobFeeConfig{MaxBlobFeeCap: 1_000, PollInterval: time.Second}.withDefaults()
+	require.NoError(err)
+	require.Equal(defaults.Multiplier, config.Multiplier)
+	require.Equal(defaults.BumpPercent, config.BumpPercent)
+	require.Equal(defaults.MaxFeeCap, config.MaxFeeCap)
+	require.Equal(uint64(1_000), config.MaxBlobFeeCap)
+	require.Equal(defaults.ResubmitInterval, config.ResubmitInterval)
+	require.Equal(time.Second, config.PollInterval)
+
+	for _, invalid := range []BlobFeeConfig{
+		{Multiplier: -1},
+		{Multiplier: math.NaN()},
+		{ResubmitInterval: -time.Second},
+		{PollInterval: -time.Second},
+	} {
+		_, err := invalid.withDefaults()
+		require.ErrorIs(err, ErrInvalidFeeConfig)
+	}
+}
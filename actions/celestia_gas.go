@@ -0,0 +1,38 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import "github.com/celestiaorg/celestia-openrpc/types/appconsts"
+
+const (
+	// CelestiaPFBGasFixedCost is the gas of a PayForBlobs transaction
+	// besides its blobs.
+	CelestiaPFBGasFixedCost = 75_000
+	// celestiaBytesPerBlobInfo is the size a blob adds to its PayForBlobs
+	// transaction, charged at celestiaTxSizeCostPerByte.
+	celestiaBytesPerBlobInfo  = 70
+	celestiaTxSizeCostPerByte = 10
+)
+
+// CelestiaBlobShares returns the number of shares a blob of [size] bytes
+// spans.
+func CelestiaBlobShares(size int) int {
+	if size <= appconsts.FirstSparseShareContentSize {
+		return 1
+	}
+	rest := size - appconsts.FirstSparseShareContentSize
+	return 1 + (rest+appconsts.ContinuationSparseShareContentSize-1)/appconsts.ContinuationSparseShareContentSize
+}
+
+// EstimateCelestiaGas returns the gas a PayForBlobs transaction with blobs
+// of [sizes] bytes uses, estimated like celestia-node does with the default
+// parameters of the chain.
+func EstimateCelestiaGas(sizes ...int) uint64 {
+	gas := uint64(CelestiaPFBGasFixedCost)
+	for _, size := range sizes {
+		gas += uint64(CelestiaBlobShares(size)) * appconsts.ShareSize * appconsts.DefaultGasPerBlobByte
+		gas += celestiaBytesPerBlobInfo * celestiaTxSizeCostPerByte
+	}
+	return gas
+}
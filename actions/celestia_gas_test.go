@@ -0,0 +1,42 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCelestiaBlobShares(t *testing.T) {
+	tests := []struct {
+		name   string
+		size   int
+		shares int
+	}{
+		{name: "Empty", size: 0, shares: 1},
+		{name: "FirstShare", size: appconsts.FirstSparseShareContentSize, shares: 1},
+		{name: "SecondShare", size: appconsts.FirstSparseShareContentSize + 1, shares: 2},
+		{
+			name:   "ThreeShares",
+			size:   appconsts.FirstSparseShareContentSize + 2*appconsts.ContinuationSparseShareContentSize,
+			shares: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.shares, CelestiaBlobShares(tt.size))
+		})
+	}
+}
+
+func TestEstimateCelestiaGas(t *testing.T) {
+	require := require.New(t)
+
+	one := EstimateCelestiaGas(100)
+	require.Equal(uint64(CelestiaPFBGasFixedCost+appconsts.ShareSize*appconsts.DefaultGasPerBlobByte+700), one)
+	// The fixed cost is paid once per transaction.
+	require.Equal(2*one-CelestiaPFBGasFixedCost, EstimateCelestiaGas(100, 100))
+}
@@ -0,0 +1,64 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+const (
+	// CelestiaNamespaceIDSize is the size of the user-specified suffix of a
+	// version 0 namespace.
+	CelestiaNamespaceIDSize = 10
+	// CelestiaNamespaceSize is the size of a full namespace: a version byte
+	// followed by a 28-byte ID.
+	CelestiaNamespaceSize = 29
+)
+
+var ErrInvalidCelestiaNamespace = errors.New("invalid celestia namespace")
+
+// CelestiaNamespaceID is the namespace ID blobs are submitted under when
+// neither a namespace nor a submitter is set.
+var CelestiaNamespaceID = []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+// CelestiaNamespace returns the version 0 blob namespace of [id]. [id] is
+// either a namespace ID of up to [CelestiaNamespaceIDSize] bytes, left padded
+// with zeros, or a full [CelestiaNamespaceSize] bytes namespace. Reserved
+// namespaces are rejected.
+func CelestiaNamespace(id []byte) (share.Namespace, error) {
+	var (
+		namespace share.Namespace
+		err       error
+	)
+	switch {
+	case len(id) == CelestiaNamespaceSize:
+		namespace = share.Namespace(id)
+		if namespace.Version() != 0 {
+			return nil, fmt.Errorf("%w: version %d is not supported", ErrInvalidCelestiaNamespace, namespace.Version())
+		}
+		err = namespace.ValidateForBlob()
+	case len(id) == 0 || len(id) > CelestiaNamespaceIDSize:
+		return nil, fmt.Errorf("%w: size %d", ErrInvalidCelestiaNamespace, len(id))
+	default:
+		namespace, err = share.NewBlobNamespaceV0(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCelestiaNamespace, err)
+	}
+	return namespace, nil
+}
+
+// CelestiaNamespaceFromAddress derives the namespace of [addr] from the
+// first [CelestiaNamespaceIDSize] bytes of its hash, so that every submitter
+// gets its own namespace.
+func CelestiaNamespaceFromAddress(addr codec.Address) (share.Namespace, error) {
+	h := sha256.Sum256(addr[:])
+	return CelestiaNamespace(h[:CelestiaNamespaceIDSize])
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestCelestiaNamespace(t *testing.T) {
+	full := append(make([]byte, CelestiaNamespaceSize-CelestiaNamespaceIDSize), bytes.Repeat([]byte{0x01}, CelestiaNamespaceIDSize)...)
+
+	tests := []struct {
+		name        string
+		id          []byte
+		expected    share.Namespace
+		expectedErr error
+	}{
+		{
+			name:     "ID",
+			id:       []byte{0xDE, 0xAD, 0xBE, 0xEF},
+			expected: share.Namespace(append(make([]byte, CelestiaNamespaceSize-4), 0xDE, 0xAD, 0xBE, 0xEF)),
+		},
+		{
+			name:     "FullNamespace",
+			id:       full,
+			expected: share.Namespace(full),
+		},
+		{
+			name:        "Empty",
+			expectedErr: ErrInvalidCelestiaNamespace,
+		},
+		{
+			name:        "IDTooLarge",
+			id:          make([]byte, CelestiaNamespaceIDSize+1),
+			expectedErr: ErrInvalidCelestiaNamespace,
+		},
+		{
+			name:        "Reserved",
+			id:          []byte{0x01},
+			expectedErr: ErrInvalidCelestiaNamespace,
+		},
+		{
+			name:        "MissingZeroPrefix",
+			id:          append([]byte{0x00}, bytes.Repeat([]byte{0x01}, CelestiaNamespaceSize-1)...),
+			expectedErr: ErrInvalidCelestiaNamespace,
+		},
+		{
+			name:        "UnsupportedVersion",
+			id:          append([]byte{0xFF}, full[1:]...),
+			expectedErr: ErrInvalidCelestiaNamespace,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			namespace, err := CelestiaNamespace(tt.id)
+			require.ErrorIs(err, tt.expectedErr)
+			require.Equal(tt.expected, namespace)
+		})
+	}
+}
+
+func TestCelestiaNamespaceFromAddress(t *testing.T) {
+	require := require.New(t)
+
+	addr := codectest.NewRandomAddress()
+	namespace, err := CelestiaNamespaceFromAddress(addr)
+	require.NoError(err)
+	require.Len(namespace, CelestiaNamespaceSize)
+	require.NoError(namespace.ValidateForBlob())
+
+	other, err := CelestiaNamespaceFromAddress(codectest.NewRandomAddress())
+	require.NoError(err)
+	require.NotEqual(namespace, other)
+
+	action := &SendCelestiaAction{Submitter: addr}
+	actionNamespace, err := action.GetNamespace()
+	require.NoError(err)
+	require.Equal(namespace, actionNamespace)
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"github.com/ava-labs/hypersdk/chain"
+
+	smath "github.com/ava-labs/avalanchego/utils/math"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+// DAFeesKey is the key [chain.Rules.FetchCustom] serves [DAFees] under.
+const DAFeesKey = "daFees"
+
+// DALayerFee is the price of registering a blob posted to a DA layer, in
+// the smallest denomination of the native token.
+type DALayerFee struct {
+	BaseFee    uint64 `json:"baseFee"`
+	FeePerByte uint64 `json:"feePerByte"`
+}
+
+// Fee returns the fee charged for a blob of [size] bytes.
+func (f DALayerFee) Fee(size uint64) (uint64, error) {
+	variable, err := smath.Mul(f.FeePerByte, size)
+	if err != nil {
+		return 0, err
+	}
+	return smath.Add(f.BaseFee, variable)
+}
+
+// DAFees holds the [DALayerFee] of every DA layer. It is read from genesis.
+type DAFees struct {
+	Ethereum DALayerFee `json:"ethereum"`
+	Celestia DALayerFee `json:"celestia"`
+	Avail    DALayerFee `json:"avail"`
+	EigenDA  DALayerFee `json:"eigenda"`
+	// Manifest is the price of registering a manifest, by the bytes of the
+	// blob IDs it lists.
+	Manifest DALayerFee `json:"manifest"`
+}
+
+func NewDefaultDAFees() DAFees {
+	return DAFees{
+		Ethereum: DALayerFee{BaseFee: 1_000_000, FeePerByte: 100},
+		Celestia: DALayerFee{BaseFee: 100_000, FeePerByte: 10},
+		Avail:    DALayerFee{BaseFee: 100_000, FeePerByte: 10},
+		EigenDA:  DALayerFee{BaseFee: 10_000, FeePerByte: 1},
+		Manifest: DALayerFee{BaseFee: 100_000, FeePerByte: 10},
+	}
+}
+
+// Layer returns the fee of [layer].
+func (f DAFees) Layer(layer uint8) (DALayerFee, bool) {
+	switch layer {
+	case mconsts.EthereumLayerID:
+		return f.Ethereum, true
+	case mconsts.CelestiaLayerID:
+		return f.Celestia, true
+	case mconsts.AvailLayerID:
+		return f.Avail, true
+	case mconsts.EigenDALayerID:
+		return f.EigenDA, true
+	default:
+		return DALayerFee{}, false
+	}
+}
+
+// GetDAFees returns the [DAFees] of [r], falling back to the defaults if
+// the rules do not define any.
+func GetDAFees(r chain.Rules) DAFees {
+	if v, ok := r.FetchCustom(DAFeesKey); ok {
+		if fees, ok := v.(DAFees); ok {
+			return fees
+		}
+	}
+	return NewDefaultDAFees()
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+var ErrEigenDANoQuorum = errors.New("eigenda certificate has no quorum")
+
+// EigenDAQuorumResult is the security a blob got in one quorum: the
+// thresholds it was dispersed with and the share of stake that signed its
+// batch.
+type EigenDAQuorumResult struct {
+	EigenDAQuorum
+	SignedPercentage uint8 `json:"signed_percentage"`
+}
+
+// EigenDACertificate is what the disperser returns for a finalized blob. It
+// locates the blob in its batch and proves the batch was confirmed on
+// Ethereum.
+type EigenDACertificate struct {
+	BatchHeaderHash         codec.Bytes `json:"batch_header_hash"`
+	BlobIndex               uint32      `json:"blob_index"`
+	BatchRoot               codec.Bytes `json:"batch_root"`
+	ReferenceBlockNumber    uint32      `json:"reference_block_number"`
+	ConfirmationBlockNumber uint32      `json:"confirmation_block_number"`
+	// Commitment is the KZG commitment of the blob, as the X and Y
+	// coordinates of the G1 point.
+	Commitment codec.Bytes `json:"commitment"`
+	// DataLength is the length of the encoded blob in field elements.
+	DataLength uint32 `json:"data_length"`
+	// InclusionProof proves the blob header is part of [BatchRoot] and
+	// QuorumIndexes locate the quorums of the blob in the batch header.
+	InclusionProof codec.Bytes           `json:"inclusion_proof"`
+	QuorumIndexes  codec.Bytes           `json:"quorum_indexes"`
+	Quorums        []EigenDAQuorumResult `json:"quorums"`
+}
+
+// QuorumIDs returns the IDs of the quorums the blob was dispersed to.
+func (c *EigenDACertificate) QuorumIDs() []uint8 {
+	ids := make([]uint8, len(c.Quorums))
+	for i, quorum := range c.Quorums {
+		ids[i] = quorum.ID
+	}
+	return ids
+}
+
+// newEigenDAQuorumResults pairs the quorum parameters of a blob header with
+// the signed percentages of its batch header. A quorum missing from the
+// batch header is reported with a signed percentage of 0.
+func newEigenDAQuorumResults(dispersed []EigenDAQuorum, quorumNumbers []byte, signedPercentages []byte) []EigenDAQuorumResult {
+	results := make([]EigenDAQuorumResult, len(dispersed))
+	for i, quorum := range dispersed {
+		results[i].EigenDAQuorum = quorum
+		if j := bytes.IndexByte(quorumNumbers, quorum.ID); j >= 0 && j < len(signedPercentages) {
+			results[i].SignedPercentage = signedPercentages[j]
+		}
+	}
+	return results
+}
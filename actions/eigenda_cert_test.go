@@ -0,0 +1,44 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEigenDAQuorumResults(t *testing.T) {
+	require := require.New(t)
+
+	dispersed := []EigenDAQuorum{
+		{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+		{ID: 1, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+		{ID: 2, AdversaryThreshold: 40, ConfirmationThreshold: 70},
+	}
+	// Quorum 2 is missing from the batch header and quorum 3 is not part of
+	// the blob.
+	results := newEigenDAQuorumResults(dispersed, []byte{3, 1, 0}, []byte{90, 80, 70})
+	require.Equal([]EigenDAQuorumResult{
+		{EigenDAQuorum: dispersed[0], SignedPercentage: 70},
+		{EigenDAQuorum: dispersed[1], SignedPercentage: 80},
+		{EigenDAQuorum: dispersed[2]},
+	}, results)
+
+	cert := &EigenDACertificate{
+		BatchHeaderHash: []byte{0x01, 0x02},
+		BlobIndex:       7,
+		Quorums:         results,
+	}
+	require.Equal([]uint8{0, 1, 2}, cert.QuorumIDs())
+
+	b, err := json.Marshal(cert)
+	require.NoError(err)
+	var parsed EigenDACertificate
+	require.NoError(json.Unmarshal(b, &parsed))
+	require.Equal(cert.BatchHeaderHash, parsed.BatchHeaderHash)
+	require.Equal(cert.BlobIndex, parsed.BlobIndex)
+	require.Equal(cert.Quorums, parsed.Quorums)
+}
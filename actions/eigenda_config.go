@@ -0,0 +1,135 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrEigenDAHostEmpty         = errors.New("eigenda disperser host is not set")
+	ErrEigenDARetrieverNotSet   = errors.New("eigenda retriever host is not set")
+	ErrEigenDAInvalidThresholds = errors.New("invalid eigenda quorum thresholds")
+	ErrEigenDADuplicateQuorum   = errors.New("duplicate eigenda quorum")
+	ErrEigenDAInvalidInterval   = errors.New("eigenda poll interval and timeout must be positive")
+	ErrEigenDAQuorumNotMet      = errors.New("eigenda quorum did not reach its confirmation threshold")
+)
+
+// EigenDAQuorum is a quorum a blob is dispersed to, with the thresholds, in
+// percent of stake, it must be secured with.
+type EigenDAQuorum struct {
+	ID                    uint8 `json:"id"`
+	AdversaryThreshold    uint8 `json:"adversary_threshold"`
+	ConfirmationThreshold uint8 `json:"confirmation_threshold"`
+}
+
+// EigenDADisperserConfig selects the EigenDA disperser blobs are sent to and
+// the security they are dispersed with.
+type EigenDADisperserConfig struct {
+	Host   string `json:"host"`
+	Port   string `json:"port"`
+	UseTLS bool   `json:"use_tls"`
+	// Timeout bounds every request to the disperser.
+	Timeout time.Duration `json:"timeout"`
+	// Quorums are dispersed to on top of the quorums the disperser requires.
+	// A required quorum may be listed to enforce stricter thresholds on it.
+	Quorums []EigenDAQuorum `json:"quorums"`
+	// PollInterval is how often the status of a dispersal is checked and
+	// StatusTimeout is how long a dispersal may take to be finalized.
+	PollInterval  time.Duration `json:"poll_interval"`
+	StatusTimeout time.Duration `json:"status_timeout"`
+}
+
+// NewDefaultEigenDADisperserConfig returns the configuration of the Holesky
+// testnet disperser.
+func NewDefaultEigenDADisperserConfig() EigenDADisperserConfig {
+	return EigenDADisperserConfig{
+		Host:          "disperser-holesky.eigenda.xyz",
+		Port:          "443",
+		UseTLS:        true,
+		Timeout:       10 * time.Second,
+		PollInterval:  5 * time.Second,
+		StatusTimeout: 30 * time.Minute,
+	}
+}
+
+// EigenDARetrieverConfig selects the EigenDA retriever blobs are read back
+// from. The retriever is run by the reader, so there is no default host.
+type EigenDARetrieverConfig struct {
+	Host    string        `json:"host"`
+	Port    string        `json:"port"`
+	UseTLS  bool          `json:"use_tls"`
+	Timeout time.Duration `json:"timeout"`
+}
+
+func NewDefaultEigenDARetrieverConfig() EigenDARetrieverConfig {
+	return EigenDARetrieverConfig{
+		Port:    "32011",
+		Timeout: 30 * time.Second,
+	}
+}
+
+func (c EigenDADisperserConfig) Validate() error {
+	if c.Host == "" {
+		return ErrEigenDAHostEmpty
+	}
+	if c.PollInterval <= 0 || c.StatusTimeout <= 0 {
+		return ErrEigenDAInvalidInterval
+	}
+	seen := make(map[uint8]struct{}, len(c.Quorums))
+	for _, quorum := range c.Quorums {
+		if _, ok := seen[quorum.ID]; ok {
+			return fmt.Errorf("%w: %d", ErrEigenDADuplicateQuorum, quorum.ID)
+		}
+		seen[quorum.ID] = struct{}{}
+		if quorum.ConfirmationThreshold > 100 || quorum.AdversaryThreshold >= quorum.ConfirmationThreshold {
+			return fmt.Errorf("%w: quorum %d", ErrEigenDAInvalidThresholds, quorum.ID)
+		}
+	}
+	return nil
+}
+
+// QuorumIDs returns the IDs of [Quorums].
+func (c EigenDADisperserConfig) QuorumIDs() []uint8 {
+	ids := make([]uint8, len(c.Quorums))
+	for i, quorum := range c.Quorums {
+		ids[i] = quorum.ID
+	}
+	return ids
+}
+
+// checkSignedPercentages ensures every configured quorum signed the batch
+// with at least its confirmation threshold. [quorumNumbers] and
+// [signedPercentages] are the matching fields of the batch header.
+func (c EigenDADisperserConfig) checkSignedPercentages(quorumNumbers []byte, signedPercentages []byte) error {
+	for _, quorum := range c.Quorums {
+		i := bytes.IndexByte(quorumNumbers, quorum.ID)
+		if i < 0 || i >= len(signedPercentages) || signedPercentages[i] < quorum.ConfirmationThreshold {
+			return fmt.Errorf("%w: quorum %d", ErrEigenDAQuorumNotMet, quorum.ID)
+		}
+	}
+	return nil
+}
+
+// checkQuorumParams ensures every configured quorum is part of [dispersed],
+// the quorum parameters of the blob header, with an adversary threshold at
+// least as conservative as configured.
+func (c EigenDADisperserConfig) checkQuorumParams(dispersed []EigenDAQuorum) error {
+	for _, quorum := range c.Quorums {
+		i := slices.IndexFunc(dispersed, func(d EigenDAQuorum) bool {
+			return d.ID == quorum.ID
+		})
+		if i < 0 {
+			return fmt.Errorf("%w: quorum %d not dispersed", ErrEigenDAQuorumNotMet, quorum.ID)
+		}
+		if dispersed[i].AdversaryThreshold < quorum.AdversaryThreshold {
+			return fmt.Errorf("%w: quorum %d dispersed with adversary threshold %d", ErrEigenDAInvalidThresholds, quorum.ID, dispersed[i].AdversaryThreshold)
+		}
+	}
+	return nil
+}
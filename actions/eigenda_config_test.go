@@ -0,0 +1,102 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEigenDADisperserConfigValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(*EigenDADisperserConfig)
+		expectedErr error
+	}{
+		{
+			name:   "Default",
+			modify: func(*EigenDADisperserConfig) {},
+		},
+		{
+			name: "Quorums",
+			modify: func(c *EigenDADisperserConfig) {
+				c.Quorums = []EigenDAQuorum{
+					{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+					{ID: 1, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+				}
+			},
+		},
+		{
+			name:        "EmptyHost",
+			modify:      func(c *EigenDADisperserConfig) { c.Host = "" },
+			expectedErr: ErrEigenDAHostEmpty,
+		},
+		{
+			name:        "ZeroPollInterval",
+			modify:      func(c *EigenDADisperserConfig) { c.PollInterval = 0 },
+			expectedErr: ErrEigenDAInvalidInterval,
+		},
+		{
+			name: "DuplicateQuorum",
+			modify: func(c *EigenDADisperserConfig) {
+				c.Quorums = []EigenDAQuorum{
+					{ID: 1, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+					{ID: 1, AdversaryThreshold: 40, ConfirmationThreshold: 60},
+				}
+			},
+			expectedErr: ErrEigenDADuplicateQuorum,
+		},
+		{
+			name: "AdversaryAboveConfirmation",
+			modify: func(c *EigenDADisperserConfig) {
+				c.Quorums = []EigenDAQuorum{{ID: 0, AdversaryThreshold: 60, ConfirmationThreshold: 55}}
+			},
+			expectedErr: ErrEigenDAInvalidThresholds,
+		},
+		{
+			name: "ConfirmationAbove100",
+			modify: func(c *EigenDADisperserConfig) {
+				c.Quorums = []EigenDAQuorum{{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 101}}
+			},
+			expectedErr: ErrEigenDAInvalidThresholds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewDefaultEigenDADisperserConfig()
+			tt.modify(&config)
+			require.ErrorIs(t, config.Validate(), tt.expectedErr)
+		})
+	}
+}
+
+func TestEigenDAQuorumChecks(t *testing.T) {
+	require := require.New(t)
+
+	config := NewDefaultEigenDADisperserConfig()
+	config.Quorums = []EigenDAQuorum{
+		{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+		{ID: 2, AdversaryThreshold: 40, ConfirmationThreshold: 70},
+	}
+	require.Equal([]uint8{0, 2}, config.QuorumIDs())
+
+	require.NoError(config.checkSignedPercentages([]byte{0, 1, 2}, []byte{80, 10, 70}))
+	require.ErrorIs(config.checkSignedPercentages([]byte{0, 1, 2}, []byte{80, 10, 69}), ErrEigenDAQuorumNotMet)
+	require.ErrorIs(config.checkSignedPercentages([]byte{0, 1}, []byte{80, 90}), ErrEigenDAQuorumNotMet)
+	require.ErrorIs(config.checkSignedPercentages([]byte{0, 2}, []byte{80}), ErrEigenDAQuorumNotMet)
+
+	require.NoError(config.checkQuorumParams([]EigenDAQuorum{
+		{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+		{ID: 1, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+		{ID: 2, AdversaryThreshold: 45, ConfirmationThreshold: 75},
+	}))
+	require.ErrorIs(config.checkQuorumParams([]EigenDAQuorum{
+		{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+	}), ErrEigenDAQuorumNotMet)
+	require.ErrorIs(config.checkQuorumParams([]EigenDAQuorum{
+		{ID: 0, AdversaryThreshold: 33, ConfirmationThreshold: 55},
+		{ID: 2, AdversaryThreshold: 30, ConfirmationThreshold: 70},
+	}), ErrEigenDAInvalidThresholds)
+}
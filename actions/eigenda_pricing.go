@@ -0,0 +1,47 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// eigenDABytesPerSymbol is the payload a 32 byte symbol carries once padded
+// to a field element.
+const eigenDABytesPerSymbol = 31
+
+// EigenDAPricing prices on-demand dispersals, which are paid per symbol.
+type EigenDAPricing struct {
+	// PricePerSymbol is in wei.
+	PricePerSymbol uint64 `json:"price_per_symbol"`
+	// MinSymbols is the number of symbols every dispersal is charged at
+	// least.
+	MinSymbols uint64 `json:"min_symbols"`
+}
+
+// NewDefaultEigenDAPricing returns the on-demand pricing of the payment
+// vault on mainnet.
+func NewDefaultEigenDAPricing() EigenDAPricing {
+	return EigenDAPricing{
+		PricePerSymbol: 447_000_000,
+		MinSymbols:     4096,
+	}
+}
+
+// Symbols returns the number of symbols a dispersal of [size] bytes is
+// charged for. Blobs are rounded up to a power of two symbols.
+func (p EigenDAPricing) Symbols(size int) uint64 {
+	symbols := uint64(size+eigenDABytesPerSymbol-1) / eigenDABytesPerSymbol
+	if symbols > 1 {
+		symbols = 1 << bits.Len64(symbols-1)
+	}
+	return max(symbols, p.MinSymbols)
+}
+
+// Fee returns the fee, in wei, of a dispersal of [size] bytes.
+func (p EigenDAPricing) Fee(size int) *big.Int {
+	fee := new(big.Int).SetUint64(p.Symbols(size))
+	return fee.Mul(fee, new(big.Int).SetUint64(p.PricePerSymbol))
+}
@@ -0,0 +1,33 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEigenDAPricing(t *testing.T) {
+	pricing := EigenDAPricing{PricePerSymbol: 10, MinSymbols: 4}
+	tests := []struct {
+		name    string
+		size    int
+		symbols uint64
+	}{
+		{name: "Minimum", size: 1, symbols: 4},
+		{name: "FullMinimum", size: 4 * eigenDABytesPerSymbol, symbols: 4},
+		{name: "RoundedUp", size: 4*eigenDABytesPerSymbol + 1, symbols: 8},
+		{name: "PowerOfTwo", size: 16 * eigenDABytesPerSymbol, symbols: 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			require.Equal(tt.symbols, pricing.Symbols(tt.size))
+			require.Equal(big.NewInt(int64(tt.symbols)*10), pricing.Fee(tt.size))
+		})
+	}
+}
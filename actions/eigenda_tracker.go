@@ -0,0 +1,219 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrEigenDADispersalFailed = errors.New("eigenda dispersal failed")
+	ErrEigenDAStatusTimeout   = errors.New("timed out waiting for eigenda blob to finalize")
+)
+
+// EigenDAStatus is the stage of a dispersal request.
+type EigenDAStatus uint8
+
+const (
+	EigenDAStatusUnknown EigenDAStatus = iota
+	EigenDAStatusProcessing
+	EigenDAStatusConfirmed
+	EigenDAStatusFinalized
+	EigenDAStatusFailed
+)
+
+var eigenDAStatusNames = []string{"unknown", "processing", "confirmed", "finalized", "failed"}
+
+func (s EigenDAStatus) String() string {
+	if int(s) < len(eigenDAStatusNames) {
+		return eigenDAStatusNames[s]
+	}
+	return fmt.Sprintf("status(%d)", uint8(s))
+}
+
+// Done reports whether the request will not change status anymore.
+func (s EigenDAStatus) Done() bool {
+	return s == EigenDAStatusFinalized || s == EigenDAStatusFailed
+}
+
+// EigenDAStatusUpdate reports the status of a dispersal request. Certificate
+// is set once the blob is confirmed and Err explains a failure.
+type EigenDAStatusUpdate struct {
+	RequestID   []byte
+	Status      EigenDAStatus
+	Certificate *EigenDACertificate
+	Err         error
+}
+
+// EigenDAStatusFetcher returns the current status of a dispersal request. An
+// error is treated as transient and the request is polled again.
+type EigenDAStatusFetcher interface {
+	Status(ctx context.Context, requestID []byte) (*EigenDAStatusUpdate, error)
+}
+
+type trackedRequest struct {
+	requestID []byte
+	deadline  time.Time
+	status    EigenDAStatus
+}
+
+// EigenDATracker polls in-flight dispersal requests concurrently and emits
+// every status transition on [Updates]. A request stops being tracked once it
+// is finalized, failed or timed out.
+type EigenDATracker struct {
+	fetcher      EigenDAStatusFetcher
+	pollInterval time.Duration
+	timeout      time.Duration
+	updates      chan EigenDAStatusUpdate
+
+	lock     sync.Mutex
+	requests map[string]*trackedRequest
+}
+
+func NewEigenDATracker(fetcher EigenDAStatusFetcher, pollInterval time.Duration, timeout time.Duration) *EigenDATracker {
+	return &EigenDATracker{
+		fetcher:      fetcher,
+		pollInterval: pollInterval,
+		timeout:      timeout,
+		updates:      make(chan EigenDAStatusUpdate, 64),
+		requests:     make(map[string]*trackedRequest),
+	}
+}
+
+// Track starts polling [requestID]. Tracking a request twice is a no-op.
+func (t *EigenDATracker) Track(requestID []byte) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	key := string(requestID)
+	if _, ok := t.requests[key]; ok {
+		return
+	}
+	t.requests[key] = &trackedRequest{
+		requestID: requestID,
+		deadline:  time.Now().Add(t.timeout),
+	}
+}
+
+// Pending returns the number of requests still tracked.
+func (t *EigenDATracker) Pending() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return len(t.requests)
+}
+
+// Updates returns the channel status transitions are emitted on. It is
+// closed when [Run] returns.
+func (t *EigenDATracker) Updates() <-chan EigenDAStatusUpdate {
+	return t.updates
+}
+
+// Run polls the tracked requests every poll interval until [ctx] is done.
+func (t *EigenDATracker) Run(ctx context.Context) error {
+	defer close(t.updates)
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.poll(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (t *EigenDATracker) poll(ctx context.Context) error {
+	t.lock.Lock()
+	requests := make([]*trackedRequest, 0, len(t.requests))
+	for _, request := range t.requests {
+		requests = append(requests, request)
+	}
+	t.lock.Unlock()
+
+	var (
+		now     = time.Now()
+		updates = make([]*EigenDAStatusUpdate, len(requests))
+		wg      sync.WaitGroup
+	)
+	for i, request := range requests {
+		if now.After(request.deadline) {
+			updates[i] = &EigenDAStatusUpdate{
+				RequestID: request.requestID,
+				Status:    EigenDAStatusFailed,
+				Err:       ErrEigenDAStatusTimeout,
+			}
+			continue
+		}
+		wg.Add(1)
+		go func(i int, request *trackedRequest) {
+			defer wg.Done()
+			update, err := t.fetcher.Status(ctx, request.requestID)
+			if err != nil {
+				return
+			}
+			update.RequestID = request.requestID
+			updates[i] = update
+		}(i, request)
+	}
+	wg.Wait()
+
+	for i, update := range updates {
+		if update == nil || update.Status == requests[i].status {
+			continue
+		}
+		requests[i].status = update.Status
+		if update.Status == EigenDAStatusFailed && update.Err == nil {
+			update.Err = ErrEigenDADispersalFailed
+		}
+		if update.Status.Done() {
+			t.lock.Lock()
+			delete(t.requests, string(update.RequestID))
+			t.lock.Unlock()
+		}
+		select {
+		case t.updates <- *update:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// WaitForEigenDAFinalization tracks [requestID] until it is finalized and
+// returns its certificate.
+func WaitForEigenDAFinalization(
+	ctx context.Context,
+	fetcher EigenDAStatusFetcher,
+	requestID []byte,
+	pollInterval time.Duration,
+	timeout time.Duration,
+) (*EigenDACertificate, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	tracker := NewEigenDATracker(fetcher, pollInterval, timeout)
+	tracker.Track(requestID)
+	go func() {
+		_ = tracker.Run(ctx)
+	}()
+
+	for update := range tracker.Updates() {
+		switch update.Status {
+		case EigenDAStatusFinalized:
+			return update.Certificate, nil
+		case EigenDAStatusFailed:
+			return nil, update.Err
+		}
+	}
+	return nil, ctx.Err()
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// scriptedFetcher replays a sequence of statuses per request. A nil status
+// is returned as a transient error and the last status is repeated.
+type scriptedFetcher struct {
+	lock    sync.Mutex
+	scripts map[string][]*EigenDAStatusUpdate
+}
+
+func (f *scriptedFetcher) Status(_ context.Context, requestID []byte) (*EigenDAStatusUpdate, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	script := f.scripts[string(requestID)]
+	if len(script) == 0 {
+		return &EigenDAStatusUpdate{Status: EigenDAStatusProcessing}, nil
+	}
+	next := script[0]
+	if len(script) > 1 {
+		f.scripts[string(requestID)] = script[1:]
+	}
+	if next == nil {
+		return nil, errUnavailable
+	}
+	update := *next
+	return &update, nil
+}
+
+func TestEigenDATracker(t *testing.T) {
+	require := require.New(t)
+
+	cert := &EigenDACertificate{BlobIndex: 3}
+	fetcher := &scriptedFetcher{scripts: map[string][]*EigenDAStatusUpdate{
+		"a": {
+			{Status: EigenDAStatusProcessing},
+			nil,
+			{Status: EigenDAStatusProcessing},
+			{Status: EigenDAStatusConfirmed, Certificate: cert},
+			{Status: EigenDAStatusFinalized, Certificate: cert},
+		},
+		"b": {
+			{Status: EigenDAStatusProcessing},
+			{Status: EigenDAStatusFailed},
+		},
+	}}
+
+	tracker := NewEigenDATracker(fetcher, time.Millisecond, time.Minute)
+	tracker.Track([]byte("a"))
+	tracker.Track([]byte("b"))
+	tracker.Track([]byte("a"))
+	require.Equal(2, tracker.Pending())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- tracker.Run(ctx)
+	}()
+
+	transitions := map[string][]EigenDAStatus{}
+	for len(transitions["a"]) < 3 || len(transitions["b"]) < 2 {
+		update := <-tracker.Updates()
+		id := string(update.RequestID)
+		transitions[id] = append(transitions[id], update.Status)
+		switch update.Status {
+		case EigenDAStatusFinalized:
+			require.Equal(cert, update.Certificate)
+		case EigenDAStatusFailed:
+			require.ErrorIs(update.Err, ErrEigenDADispersalFailed)
+		}
+	}
+	require.Equal([]EigenDAStatus{EigenDAStatusProcessing, EigenDAStatusConfirmed, EigenDAStatusFinalized}, transitions["a"])
+	require.Equal([]EigenDAStatus{EigenDAStatusProcessing, EigenDAStatusFailed}, transitions["b"])
+	require.Zero(tracker.Pending())
+
+	cancel()
+	require.ErrorIs(<-done, context.Canceled)
+	_, ok := <-tracker.Updates()
+	require.False(ok)
+}
+
+func TestEigenDATrackerTimeout(t *testing.T) {
+	require := require.New(t)
+
+	fetcher := &scriptedFetcher{scripts: map[string][]*EigenDAStatusUpdate{}}
+	_, err := WaitForEigenDAFinalization(context.Background(), fetcher, []byte("a"), time.Millisecond, 10*time.Millisecond)
+	require.ErrorIs(err, ErrEigenDAStatusTimeout)
+}
+
+func TestWaitForEigenDAFinalization(t *testing.T) {
+	require := require.New(t)
+
+	cert := &EigenDACertificate{BlobIndex: 1}
+	fetcher := &scriptedFetcher{scripts: map[string][]*EigenDAStatusUpdate{
+		"a": {{Status: EigenDAStatusFinalized, Certificate: cert}},
+	}}
+	result, err := WaitForEigenDAFinalization(context.Background(), fetcher, []byte("a"), time.Millisecond, time.Minute)
+	require.NoError(err)
+	require.Equal(cert, result)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = WaitForEigenDAFinalization(ctx, fetcher, []byte("b"), time.Millisecond, time.Minute)
+	require.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	RegisterBlobCommitmentComputeUnits = 1
+	MaxBlobSize                        = 16 * 1024 * 1024
+)
+
+var (
+	ErrUnknownLayer                    = errors.New("unknown DA layer")
+	ErrCommitmentEmpty                 = errors.New("commitment is empty")
+	ErrCommitmentTooLarge              = errors.New("commitment is too large")
+	ErrNamespaceTooLarge               = errors.New("namespace is too large")
+	ErrBlobSizeZero                    = errors.New("blob size is zero")
+	ErrBlobTooLarge                    = errors.New("blob is too large")
+	ErrInvalidCiphertext               = errors.New("ciphertext digest is invalid")
+	ErrLocatorTooLarge                 = errors.New("locator is too large")
+	_                     chain.Action = (*RegisterBlobCommitment)(nil)
+)
+
+// RegisterBlobCommitment records a blob that was posted to a DA layer
+// off-chain. It never talks to the DA layer itself, so every validator
+// executes it identically.
+type RegisterBlobCommitment struct {
+	// Layer is the DA layer the blob was posted to.
+	Layer uint8 `serialize:"true" json:"layer"`
+
+	// Commitment is the layer-specific commitment to the blob (KZG versioned
+	// hash, Celestia share commitment, ...).
+	Commitment []byte `serialize:"true" json:"commitment"`
+
+	// Height is the DA layer height the blob was included at.
+	Height uint64 `serialize:"true" json:"height"`
+
+	// Namespace the blob was posted under, if the DA layer has namespaces.
+	Namespace []byte `serialize:"true" json:"namespace"`
+
+	// Size of the blob in bytes.
+	Size uint64 `serialize:"true" json:"size"`
+
+	// Ciphertext is the sha256 of the blob if it was encrypted before being
+	// posted, and empty otherwise.
+	Ciphertext []byte `serialize:"true" json:"ciphertext"`
+
+	// Locator is the layer-specific data needed to find the blob besides
+	// its height, namespace and commitment, see [storage.BlobRecord].
+	Locator []byte `serialize:"true" json:"locator"`
+}
+
+func (*RegisterBlobCommitment) GetTypeID() uint8 {
+	return mconsts.RegisterBlobCommitmentID
+}
+
+func (r *RegisterBlobCommitment) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	return state.Keys{
+		string(storage.BalanceKey(actor)):                              state.Read | state.Write,
+		string(storage.BlobKey(storage.BlobID(r.Layer, r.Commitment))): state.All,
+	}
+}
+
+func (r *RegisterBlobCommitment) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	layerFee, ok := GetDAFees(rules).Layer(r.Layer)
+	if !ok {
+		return nil, ErrUnknownLayer
+	}
+	if len(r.Commitment) == 0 {
+		return nil, ErrCommitmentEmpty
+	}
+	if len(r.Commitment) > storage.MaxCommitmentSize {
+		return nil, ErrCommitmentTooLarge
+	}
+	if len(r.Namespace) > storage.MaxNamespaceSize {
+		return nil, ErrNamespaceTooLarge
+	}
+	if r.Size == 0 {
+		return nil, ErrBlobSizeZero
+	}
+	if r.Size > MaxBlobSize {
+		return nil, ErrBlobTooLarge
+	}
+	if len(r.Ciphertext) != 0 && len(r.Ciphertext) != storage.CiphertextSize {
+		return nil, ErrInvalidCiphertext
+	}
+	if len(r.Locator) > storage.MaxLocatorSize {
+		return nil, ErrLocatorTooLarge
+	}
+	fee, err := layerFee.Fee(r.Size)
+	if err != nil {
+		return nil, err
+	}
+	blobID := storage.BlobID(r.Layer, r.Commitment)
+	_, exists, err := storage.GetBlob(ctx, mu, blobID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, storage.ErrBlobAlreadyRegistered
+	}
+	if err := storage.SetBlob(ctx, mu, blobID, &storage.BlobRecord{
+		Submitter:  actor,
+		Layer:      r.Layer,
+		Height:     r.Height,
+		Namespace:  r.Namespace,
+		Commitment: r.Commitment,
+		Size:       r.Size,
+		Timestamp:  timestamp,
+		Ciphertext: r.Ciphertext,
+		Locator:    r.Locator,
+	}); err != nil {
+		return nil, err
+	}
+	// The fee is burned, it pays for posting the blob to the DA layer.
+	balance, err := storage.SubBalance(ctx, mu, actor, fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegisterBlobCommitmentResult{
+		BlobID:           blobID,
+		Fee:              fee,
+		SubmitterBalance: balance,
+	}, nil
+}
+
+func (*RegisterBlobCommitment) ComputeUnits(chain.Rules) uint64 {
+	return RegisterBlobCommitmentComputeUnits
+}
+
+func (*RegisterBlobCommitment) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RegisterBlobCommitmentResult)(nil)
+
+type RegisterBlobCommitmentResult struct {
+	BlobID           ids.ID `serialize:"true" json:"blob_id"`
+	Fee              uint64 `serialize:"true" json:"fee"`
+	SubmitterBalance uint64 `serialize:"true" json:"submitter_balance"`
+}
+
+func (*RegisterBlobCommitmentResult) GetTypeID() uint8 {
+	return mconsts.RegisterBlobCommitmentID
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestRegisterBlobCommitmentAction(t *testing.T) {
+	addr := codectest.NewRandomAddress()
+	commitment := bytes.Repeat([]byte{0x01}, 32)
+	namespace := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+	blobID := storage.BlobID(consts.CelestiaLayerID, commitment)
+	rules := genesis.NewDefaultRules()
+	ciphertext := bytes.Repeat([]byte{0x02}, storage.CiphertextSize)
+	locator := bytes.Repeat([]byte{0x03}, storage.MaxLocatorSize)
+	fee := NewDefaultDAFees().Celestia.BaseFee + NewDefaultDAFees().Celestia.FeePerByte
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:  "UnknownLayer",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      0,
+				Commitment: commitment,
+				Size:       1,
+			},
+			ExpectedErr: ErrUnknownLayer,
+		},
+		{
+			Name:  "EmptyCommitment",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer: consts.CelestiaLayerID,
+				Size:  1,
+			},
+			ExpectedErr: ErrCommitmentEmpty,
+		},
+		{
+			Name:  "CommitmentTooLarge",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: make([]byte, storage.MaxCommitmentSize+1),
+				Size:       1,
+			},
+			ExpectedErr: ErrCommitmentTooLarge,
+		},
+		{
+			Name:  "NamespaceTooLarge",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Namespace:  make([]byte, storage.MaxNamespaceSize+1),
+				Size:       1,
+			},
+			ExpectedErr: ErrNamespaceTooLarge,
+		},
+		{
+			Name:  "ZeroSize",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+			},
+			ExpectedErr: ErrBlobSizeZero,
+		},
+		{
+			Name:  "BlobTooLarge",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Size:       MaxBlobSize + 1,
+			},
+			ExpectedErr: ErrBlobTooLarge,
+		},
+		{
+			Name:  "InvalidCiphertext",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Size:       1,
+				Ciphertext: ciphertext[1:],
+			},
+			ExpectedErr: ErrInvalidCiphertext,
+		},
+		{
+			Name:  "LocatorTooLarge",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Size:       1,
+				Locator:    make([]byte, storage.MaxLocatorSize+1),
+			},
+			ExpectedErr: ErrLocatorTooLarge,
+		},
+		{
+			Name:  "AlreadyRegistered",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Size:       1,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetBalance(context.Background(), store, addr, fee))
+				require.NoError(t, storage.SetBlob(context.Background(), store, blobID, &storage.BlobRecord{
+					Submitter:  addr,
+					Layer:      consts.CelestiaLayerID,
+					Commitment: commitment,
+					Size:       1,
+				}))
+				return store
+			}(),
+			ExpectedErr: storage.ErrBlobAlreadyRegistered,
+		},
+		{
+			Name:  "InsufficientBalance",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Size:       1,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetBalance(context.Background(), store, addr, fee-1))
+				return store
+			}(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:  "SimpleRegister",
+			Actor: addr,
+			Rules: rules,
+			Action: &RegisterBlobCommitment{
+				Layer:      consts.CelestiaLayerID,
+				Commitment: commitment,
+				Height:     100,
+				Namespace:  namespace,
+				Size:       1,
+				Ciphertext: ciphertext,
+				Locator:    locator,
+			},
+			State: func() state.Mutable {
+				store := chaintest.NewInMemoryStore()
+				require.NoError(t, storage.SetBalance(context.Background(), store, addr, fee+1))
+				return store
+			}(),
+			Timestamp: 1000,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, addr)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1), balance)
+				record, exists, err := storage.GetBlob(ctx, store, blobID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.BlobRecord{
+					Submitter:  addr,
+					Layer:      consts.CelestiaLayerID,
+					Height:     100,
+					Namespace:  namespace,
+					Commitment: commitment,
+					Size:       1,
+					Timestamp:  1000,
+					Ciphertext: ciphertext,
+					Locator:    locator,
+				}, record)
+			},
+			ExpectedOutputs: &RegisterBlobCommitmentResult{
+				BlobID:           blobID,
+				Fee:              fee,
+				SubmitterBalance: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.Run(context.Background(), t)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/state"
+
+	mconsts "github.com/ava-labs/hypersdk-starter-kit/consts"
+)
+
+const (
+	RegisterBlobManifestComputeUnits        = 1
+	RegisterBlobManifestComputeUnitsPerBlob = 1
+)
+
+var (
+	ErrManifestEmpty                      = errors.New("manifest is empty")
+	ErrManifestTooLarge                   = errors.New("manifest is too large")
+	ErrDuplicateManifestBlob              = errors.New("blob is listed twice in manifest")
+	ErrBlobNotRegistered                  = errors.New("blob is not registered")
+	_                        chain.Action = (*RegisterBlobManifest)(nil)
+)
+
+// RegisterBlobManifest groups blobs registered with [RegisterBlobCommitment]
+// into a single ordered batch, so a rollup can reference the batch by the ID
+// of the manifest.
+type RegisterBlobManifest struct {
+	// Blobs are the IDs of the blobs of the batch, in order. They may have
+	// been posted to different DA layers.
+	Blobs []ids.ID `serialize:"true" json:"blobs"`
+}
+
+func (*RegisterBlobManifest) GetTypeID() uint8 {
+	return mconsts.RegisterBlobManifestID
+}
+
+func (r *RegisterBlobManifest) StateKeys(actor codec.Address, _ ids.ID) state.Keys {
+	keys := state.Keys{
+		string(storage.BalanceKey(actor)): state.Read | state.Write,
+	}
+	// Execute rejects such manifests before reading any other key.
+	if len(r.Blobs) == 0 || len(r.Blobs) > storage.MaxManifestBlobs {
+		return keys
+	}
+	manifestID := storage.ManifestID(actor, storage.ManifestRoot(r.Blobs))
+	keys.Add(string(storage.ManifestKey(manifestID)), state.All)
+	for _, blobID := range r.Blobs {
+		keys.Add(string(storage.BlobKey(blobID)), state.Read)
+	}
+	return keys
+}
+
+func (r *RegisterBlobManifest) Execute(
+	ctx context.Context,
+	rules chain.Rules,
+	mu state.Mutable,
+	timestamp int64,
+	actor codec.Address,
+	_ ids.ID,
+) (codec.Typed, error) {
+	if len(r.Blobs) == 0 {
+		return nil, ErrManifestEmpty
+	}
+	if len(r.Blobs) > storage.MaxManifestBlobs {
+		return nil, ErrManifestTooLarge
+	}
+	fee, err := GetDAFees(rules).Manifest.Fee(uint64(len(r.Blobs)) * ids.IDLen)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		seen = make(map[ids.ID]struct{}, len(r.Blobs))
+		size uint64
+	)
+	for _, blobID := range r.Blobs {
+		if _, ok := seen[blobID]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateManifestBlob, blobID)
+		}
+		seen[blobID] = struct{}{}
+		record, exists, err := storage.GetBlob(ctx, mu, blobID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrBlobNotRegistered, blobID)
+		}
+		size += record.Size
+	}
+
+	root := storage.ManifestRoot(r.Blobs)
+	manifestID := storage.ManifestID(actor, root)
+	_, exists, err := storage.GetManifest(ctx, mu, manifestID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, storage.ErrManifestAlreadyRegistered
+	}
+	if err := storage.SetManifest(ctx, mu, manifestID, &storage.ManifestRecord{
+		Submitter: actor,
+		Root:      root,
+		Blobs:     r.Blobs,
+		Size:      size,
+		Timestamp: timestamp,
+	}); err != nil {
+		return nil, err
+	}
+	// The fee is burned like the fee of [RegisterBlobCommitment].
+	balance, err := storage.SubBalance(ctx, mu, actor, fee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RegisterBlobManifestResult{
+		ManifestID:       manifestID,
+		Root:             root,
+		Size:             size,
+		Fee:              fee,
+		SubmitterBalance: balance,
+	}, nil
+}
+
+func (r *RegisterBlobManifest) ComputeUnits(chain.Rules) uint64 {
+	return RegisterBlobManifestComputeUnits + uint64(len(r.Blobs))*RegisterBlobManifestComputeUnitsPerBlob
+}
+
+func (*RegisterBlobManifest) ValidRange(chain.Rules) (int64, int64) {
+	// Returning -1, -1 means that the action is always valid.
+	return -1, -1
+}
+
+var _ codec.Typed = (*RegisterBlobManifestResult)(nil)
+
+type RegisterBlobManifestResult struct {
+	ManifestID ids.ID `serialize:"true" json:"manifest_id"`
+	Root       ids.ID `serialize:"true" json:"root"`
+	// Size is the total size of the blobs of the manifest.
+	Size             uint64 `serialize:"true" json:"size"`
+	Fee              uint64 `serialize:"true" json:"fee"`
+	SubmitterBalance uint64 `serialize:"true" json:"submitter_balance"`
+}
+
+func (*RegisterBlobManifestResult) GetTypeID() uint8 {
+	return mconsts.RegisterBlobManifestID
+}
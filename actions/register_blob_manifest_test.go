@@ -0,0 +1,162 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/genesis"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+func TestRegisterBlobManifestAction(t *testing.T) {
+	addr := codectest.NewRandomAddress()
+	celestiaBlob := storage.BlobID(consts.CelestiaLayerID, []byte{0x01})
+	eigenDABlob := storage.BlobID(consts.EigenDALayerID, []byte{0x02})
+	blobs := []ids.ID{celestiaBlob, eigenDABlob}
+	root := storage.ManifestRoot(blobs)
+	manifestID := storage.ManifestID(addr, root)
+	rules := genesis.NewDefaultRules()
+	fee := NewDefaultDAFees().Manifest.BaseFee + 2*ids.IDLen*NewDefaultDAFees().Manifest.FeePerByte
+
+	newStore := func() state.Mutable {
+		store := chaintest.NewInMemoryStore()
+		require.NoError(t, storage.SetBalance(context.Background(), store, addr, fee+1))
+		for i, blobID := range blobs {
+			require.NoError(t, storage.SetBlob(context.Background(), store, blobID, &storage.BlobRecord{
+				Submitter:  addr,
+				Layer:      consts.CelestiaLayerID,
+				Commitment: []byte{byte(i + 1)},
+				Size:       uint64(100 * (i + 1)),
+			}))
+		}
+		return store
+	}
+
+	tests := []chaintest.ActionTest{
+		{
+			Name:        "EmptyManifest",
+			Actor:       addr,
+			Rules:       rules,
+			Action:      &RegisterBlobManifest{},
+			ExpectedErr: ErrManifestEmpty,
+		},
+		{
+			Name:        "ManifestTooLarge",
+			Actor:       addr,
+			Rules:       rules,
+			Action:      &RegisterBlobManifest{Blobs: make([]ids.ID, storage.MaxManifestBlobs+1)},
+			ExpectedErr: ErrManifestTooLarge,
+		},
+		{
+			Name:        "DuplicateBlob",
+			Actor:       addr,
+			Rules:       rules,
+			Action:      &RegisterBlobManifest{Blobs: []ids.ID{celestiaBlob, eigenDABlob, celestiaBlob}},
+			State:       newStore(),
+			ExpectedErr: ErrDuplicateManifestBlob,
+		},
+		{
+			Name:        "UnknownBlob",
+			Actor:       addr,
+			Rules:       rules,
+			Action:      &RegisterBlobManifest{Blobs: []ids.ID{celestiaBlob, ids.GenerateTestID()}},
+			State:       newStore(),
+			ExpectedErr: ErrBlobNotRegistered,
+		},
+		{
+			Name:   "AlreadyRegistered",
+			Actor:  addr,
+			Rules:  rules,
+			Action: &RegisterBlobManifest{Blobs: blobs},
+			State: func() state.Mutable {
+				store := newStore()
+				require.NoError(t, storage.SetManifest(context.Background(), store, manifestID, &storage.ManifestRecord{
+					Submitter: addr,
+					Root:      root,
+					Blobs:     blobs,
+					Size:      300,
+				}))
+				return store
+			}(),
+			ExpectedErr: storage.ErrManifestAlreadyRegistered,
+		},
+		{
+			Name:   "InsufficientBalance",
+			Actor:  addr,
+			Rules:  rules,
+			Action: &RegisterBlobManifest{Blobs: blobs},
+			State: func() state.Mutable {
+				store := newStore()
+				require.NoError(t, storage.SetBalance(context.Background(), store, addr, fee-1))
+				return store
+			}(),
+			ExpectedErr: storage.ErrInvalidBalance,
+		},
+		{
+			Name:      "SimpleRegister",
+			Actor:     addr,
+			Rules:     rules,
+			Action:    &RegisterBlobManifest{Blobs: blobs},
+			State:     newStore(),
+			Timestamp: 1000,
+			Assertion: func(ctx context.Context, t *testing.T, store state.Mutable) {
+				balance, err := storage.GetBalance(ctx, store, addr)
+				require.NoError(t, err)
+				require.Equal(t, uint64(1), balance)
+				record, exists, err := storage.GetManifest(ctx, store, manifestID)
+				require.NoError(t, err)
+				require.True(t, exists)
+				require.Equal(t, &storage.ManifestRecord{
+					Submitter: addr,
+					Root:      root,
+					Blobs:     blobs,
+					Size:      300,
+					Timestamp: 1000,
+				}, record)
+			},
+			ExpectedOutputs: &RegisterBlobManifestResult{
+				ManifestID:       manifestID,
+				Root:             root,
+				Size:             300,
+				Fee:              fee,
+				SubmitterBalance: 1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt.Run(context.Background(), t)
+	}
+}
+
+func TestRegisterBlobManifestStateKeys(t *testing.T) {
+	require := require.New(t)
+
+	addr := codectest.NewRandomAddress()
+	blobs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	keys := (&RegisterBlobManifest{Blobs: blobs}).StateKeys(addr, ids.Empty)
+	require.Len(keys, 4)
+	require.Equal(state.Read|state.Write, keys[string(storage.BalanceKey(addr))])
+	require.Equal(state.Read, keys[string(storage.BlobKey(blobs[1]))])
+	manifestKey := storage.ManifestKey(storage.ManifestID(addr, storage.ManifestRoot(blobs)))
+	require.Equal(state.All, keys[string(manifestKey)])
+
+	// Manifests Execute rejects only declare the balance key.
+	tooLarge := make([]ids.ID, storage.MaxManifestBlobs+1)
+	for i := range tooLarge {
+		tooLarge[i] = ids.GenerateTestID()
+	}
+	keys = (&RegisterBlobManifest{Blobs: tooLarge}).StateKeys(addr, ids.Empty)
+	require.Len(keys, 1)
+	require.Contains(keys, string(storage.BalanceKey(addr)))
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/availproject/avail-go-sdk/src/sdk"
+	"github.com/availproject/avail-go-sdk/src/sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	ErrAvailExtrinsicIndex = errors.New("extrinsic index out of range")
+	ErrAvailAppIDMismatch  = errors.New("extrinsic was submitted under another app ID")
+	ErrAvailNoDataRoot     = errors.New("block header has no data root")
+)
+
+// availHeader holds the fields of an Avail block header used to verify
+// data proofs.
+type availHeader struct {
+	Extension struct {
+		V3 *struct {
+			Commitment struct {
+				DataRoot common.Hash `json:"dataRoot"`
+			} `json:"commitment"`
+		} `json:"V3"`
+	} `json:"extension"`
+}
+
+// availDataProofResponse is the response of kate_queryDataProof.
+type availDataProofResponse struct {
+	DataProof AvailDataProof `json:"dataProof"`
+}
+
+// RetrieveAvailData returns the data submitted by the extrinsic at [index] in
+// the block with [blockHash], after checking it was submitted under [appID]
+// and that its data proof matches the data root of the block header.
+func RetrieveAvailData(
+	ctx context.Context,
+	api *sdk.SubstrateAPI,
+	blockHash string,
+	index uint32,
+	appID uint32,
+) ([]byte, error) {
+	hash, err := types.NewHashFromHexString(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := api.RPC.Chain.GetBlock(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", blockHash, err)
+	}
+	if index >= uint32(len(block.Block.Extrinsics)) {
+		return nil, fmt.Errorf("%w: %d", ErrAvailExtrinsicIndex, index)
+	}
+	ext := block.Block.Extrinsics[index]
+	if extAppID := uint32(ext.Signature.AppID.Int64()); extAppID != appID {
+		return nil, fmt.Errorf("%w: %d", ErrAvailAppIDMismatch, extAppID)
+	}
+	data, err := decodeScaleBytes(ext.Method.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyAvailData(ctx, api, hash, index, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// verifyAvailData checks [data] against the data root of the block with
+// [blockHash] using the proof served by the node.
+func verifyAvailData(ctx context.Context, api *sdk.SubstrateAPI, blockHash types.Hash, index uint32, data []byte) error {
+	var header availHeader
+	if err := api.Client.CallContext(ctx, &header, "chain_getHeader", blockHash.Hex()); err != nil {
+		return fmt.Errorf("failed to get header: %w", err)
+	}
+	if header.Extension.V3 == nil {
+		return ErrAvailNoDataRoot
+	}
+	var resp availDataProofResponse
+	if err := api.Client.CallContext(ctx, &resp, "kate_queryDataProof", index, blockHash.Hex()); err != nil {
+		return fmt.Errorf("failed to get data proof: %w", err)
+	}
+	return VerifyAvailDataProof(&resp.DataProof, data, header.Extension.V3.Commitment.DataRoot)
+}
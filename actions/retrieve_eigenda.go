@@ -0,0 +1,84 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/retriever"
+	"github.com/Layr-Labs/eigenda/encoding/utils/codec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// EigenDARetriever reads blobs back from the EigenDA operators through a
+// retriever service.
+type EigenDARetriever struct {
+	config EigenDARetrieverConfig
+	conn   *grpc.ClientConn
+	client retriever.RetrieverClient
+}
+
+func NewEigenDARetriever(config EigenDARetrieverConfig) (*EigenDARetriever, error) {
+	if config.Host == "" {
+		return nil, ErrEigenDARetrieverNotSet
+	}
+	creds := insecure.NewCredentials()
+	if config.UseTLS {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.Dial(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to eigenda retriever: %w", err)
+	}
+	return &EigenDARetriever{
+		config: config,
+		conn:   conn,
+		client: retriever.NewRetrieverClient(conn),
+	}, nil
+}
+
+func (r *EigenDARetriever) Close() error {
+	return r.conn.Close()
+}
+
+// RetrieveBlob returns the blob located by [cert], decoded from its field
+// elements. The blob is read from the first quorum that serves it, so the
+// result may carry trailing zero bytes up to the size of its last field
+// element group.
+func (r *EigenDARetriever) RetrieveBlob(ctx context.Context, cert *EigenDACertificate) ([]byte, error) {
+	if len(cert.Quorums) == 0 {
+		return nil, ErrEigenDANoQuorum
+	}
+	var errs error
+	for _, quorum := range cert.Quorums {
+		data, err := r.retrieve(ctx, cert, quorum.ID)
+		if err == nil {
+			return data, nil
+		}
+		errs = errors.Join(errs, fmt.Errorf("quorum %d: %w", quorum.ID, err))
+	}
+	return nil, fmt.Errorf("failed to retrieve eigenda blob: %w", errs)
+}
+
+func (r *EigenDARetriever) retrieve(ctx context.Context, cert *EigenDACertificate, quorumID uint8) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	reply, err := r.client.RetrieveBlob(ctx, &retriever.BlobRequest{
+		BatchHeaderHash:      cert.BatchHeaderHash,
+		BlobIndex:            cert.BlobIndex,
+		ReferenceBlockNumber: cert.ReferenceBlockNumber,
+		QuorumId:             uint32(quorumID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codec.RemoveEmptyByteFromPaddedBytes(reply.GetData()), nil
+}
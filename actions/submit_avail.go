@@ -1,35 +1,148 @@
 package actions
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 
-	"github.com/availproject/avail-go-sdk/src/config"
 	"github.com/availproject/avail-go-sdk/src/sdk"
 	"github.com/availproject/avail-go-sdk/src/sdk/tx"
+	"github.com/availproject/avail-go-sdk/src/sdk/types"
+)
+
+var (
+	ErrAvailSeedEmpty         = errors.New("avail seed is not set")
+	ErrAvailAppKeyNotFound    = errors.New("avail app key not found")
+	ErrAvailExtrinsicNotFound = errors.New("submitted extrinsic not found in block")
 )
 
 type SendAvailAction struct {
 	Seed       string
 	Data       string
 	NetworkURL string
+	// AppID is the application the data is submitted under. It is ignored
+	// if AppKey is set.
+	AppID uint32
+	// AppKey names the application the data is submitted under. The key is
+	// registered, owned by the account of Seed, if it does not exist yet.
+	AppKey string
+	// WaitForFinalization makes Execute wait for the block to be finalized
+	// instead of only included.
+	WaitForFinalization bool
+}
+
+type SendAvailActionResult struct {
+	BlockHash      string `json:"block_hash"`
+	TxHash         string `json:"tx_hash"`
+	BlockNumber    uint32 `json:"block_number"`
+	ExtrinsicIndex uint32 `json:"extrinsic_index"`
+	AppID          uint32 `json:"app_id"`
+	Finalized      bool   `json:"finalized"`
 }
 
-func (a *SendAvailAction) Execute(ctx context.Context) (string, string, error) {
-	cfg, err := config.LoadConfig()
+// appKeyInfo is the value of the DataAvailability.AppKeys storage map.
+type appKeyInfo struct {
+	Owner types.AccountID
+	ID    types.UCompact
+}
+
+func (a *SendAvailAction) Execute(ctx context.Context) (*SendAvailActionResult, error) {
+	if a.Seed == "" {
+		return nil, ErrAvailSeedEmpty
+	}
+	keyring, err := sdk.KeyringFromSeed(a.Seed)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to load config: %w", err)
+		return nil, fmt.Errorf("failed to load keyring: %w", err)
 	}
 
 	api, err := sdk.NewSDK(a.NetworkURL)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to initialize Avail SDK: %w", err)
+		return nil, fmt.Errorf("failed to initialize Avail SDK: %w", err)
+	}
+	defer api.Client.Close()
+
+	waitFor := sdk.BlockInclusion
+	if a.WaitForFinalization {
+		waitFor = sdk.BlockFinalization
+	}
+
+	appID := a.AppID
+	if a.AppKey != "" {
+		appID, err = a.resolveAppID(api, waitFor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	blockHash, txHash, err := tx.SubmitData(api, a.Seed, int(appID), a.Data, waitFor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit data to Avail DA: %w", err)
+	}
+
+	block, err := api.RPC.Chain.GetBlock(blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", blockHash.Hex(), err)
+	}
+	index, err := findDataExtrinsic(block.Block.Extrinsics, keyring.PublicKey, []byte(a.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SendAvailActionResult{
+		BlockHash:      blockHash.Hex(),
+		TxHash:         txHash.Hex(),
+		BlockNumber:    uint32(block.Block.Header.Number),
+		ExtrinsicIndex: index,
+		AppID:          appID,
+		Finalized:      a.WaitForFinalization,
+	}, nil
+}
+
+// resolveAppID returns the ID of [AppKey], registering the key first if it
+// does not exist.
+func (a *SendAvailAction) resolveAppID(api *sdk.SubstrateAPI, waitFor sdk.WaitFor) (uint32, error) {
+	appID, err := getAvailAppID(api, a.AppKey)
+	if !errors.Is(err, ErrAvailAppKeyNotFound) {
+		return appID, err
+	}
+	if _, _, err := tx.CreateApplicationKey(api, a.Seed, a.AppKey, waitFor); err != nil {
+		return 0, fmt.Errorf("failed to create app key %q: %w", a.AppKey, err)
 	}
+	return getAvailAppID(api, a.AppKey)
+}
 
-	blockHash, txHash, err := tx.SubmitData(api, cfg.Seed, 1, a.Data, sdk.BlockInclusion)
+func getAvailAppID(api *sdk.SubstrateAPI, appKey string) (uint32, error) {
+	meta, err := api.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	key, err := types.CreateStorageKey(meta, "DataAvailability", "AppKeys", scaleBytes([]byte(appKey)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create storage key: %w", err)
+	}
+	var info appKeyInfo
+	ok, err := api.RPC.State.GetStorageLatest(key, &info)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to submit data to Avail DA: %w", err)
+		return 0, fmt.Errorf("failed to get app key %q: %w", appKey, err)
+	}
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrAvailAppKeyNotFound, appKey)
 	}
+	return uint32(info.ID.Int64()), nil
+}
 
-	return blockHash.Hex(), txHash.Hex(), nil
+// findDataExtrinsic returns the index of the submit_data extrinsic signed by
+// [signer] that carries [data].
+func findDataExtrinsic(extrinsics []types.Extrinsic, signer []byte, data []byte) (uint32, error) {
+	args := scaleBytes(data)
+	for i, ext := range extrinsics {
+		if !ext.IsSigned() || !bytes.Equal(ext.Signature.Signer.AsID[:], signer) {
+			continue
+		}
+		if bytes.Equal(ext.Method.Args, args) {
+			return uint32(i), nil
+		}
+	}
+	return 0, ErrAvailExtrinsicNotFound
 }
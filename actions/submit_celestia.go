@@ -3,46 +3,261 @@ package actions
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 
 	client "github.com/celestiaorg/celestia-openrpc"
 	"github.com/celestiaorg/celestia-openrpc/types/blob"
 	"github.com/celestiaorg/celestia-openrpc/types/share"
-	blobtypes "github.com/celestiaorg/celestia-app/x/blob/types"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// DefaultCelestiaMaxBatchSize keeps batches below the 2 MiB transaction
+// size limit of celestia-app.
+const DefaultCelestiaMaxBatchSize = 1_500_000
+
+var (
+	ErrEmptyCelestiaBatch      = errors.New("no payloads to submit")
+	ErrEmptyCelestiaPayload    = errors.New("empty payload")
+	ErrCelestiaBlobNotFound    = errors.New("submitted blob not found at inclusion height")
+	ErrCelestiaBlobMismatch    = errors.New("retrieved blob does not match submitted blob")
+	ErrCelestiaBlobNotIncluded = errors.New("blob is not included")
 )
 
 type SendCelestiaAction struct {
 	URL   string
 	Token string
 	Data  string
+	// Namespace is either a namespace ID or a full version 0 namespace, see
+	// [CelestiaNamespace]. If empty, the namespace is derived from
+	// [Submitter], or [CelestiaNamespaceID] is used if that is empty too.
+	Namespace []byte
+	Submitter codec.Address
+	// GasPrice is in utia per gas unit. If 0, the minimum gas price
+	// configured on the node is used.
+	GasPrice float64
+	// GasLimit is estimated by the node if 0.
+	GasLimit uint64
 }
 
+// GetNamespace returns the namespace the blob is submitted under.
+func (a *SendCelestiaAction) GetNamespace() (share.Namespace, error) {
+	switch {
+	case len(a.Namespace) > 0:
+		return CelestiaNamespace(a.Namespace)
+	case a.Submitter != codec.EmptyAddress:
+		return CelestiaNamespaceFromAddress(a.Submitter)
+	default:
+		return CelestiaNamespace(CelestiaNamespaceID)
+	}
+}
+
+// Execute submits [Data] as a single blob and returns the height it was
+// included at.
 func (a *SendCelestiaAction) Execute(ctx context.Context) (int64, error) {
-	client, err := client.NewClient(ctx, a.URL, a.Token)
+	result, err := a.ExecuteBatch(ctx, [][]byte{[]byte(a.Data)})
 	if err != nil {
-		return 0, fmt.Errorf("failed to initialize Celestia client: %w", err)
+		return 0, err
 	}
-	namespace, err := share.NewBlobNamespaceV0([]byte{0xDE, 0xAD, 0xBE, 0xEF})
+	return int64(result.Height), nil
+}
+
+// CelestiaBatchResult is the outcome of [SendCelestiaAction.ExecuteBatch].
+type CelestiaBatchResult struct {
+	Height    uint64          `json:"height"`
+	Namespace share.Namespace `json:"namespace"`
+	// Commitments and Proofs hold the commitment and inclusion proof of
+	// every payload, in order.
+	Commitments []blob.Commitment `json:"commitments"`
+	Proofs      []*blob.Proof     `json:"proofs"`
+}
+
+// ExecuteBatch submits every payload of [payloads] as its own blob in a
+// single PayForBlobs transaction. The fee is paid at [GasPrice], or at the
+// minimum gas price configured on the node if it is 0, and the gas is
+// estimated by the node unless [GasLimit] is set. Every blob is read back
+// and checked against its commitment, and its inclusion proof is fetched,
+// before returning.
+func (a *SendCelestiaAction) ExecuteBatch(ctx context.Context, payloads [][]byte) (*CelestiaBatchResult, error) {
+	if len(payloads) == 0 {
+		return nil, ErrEmptyCelestiaBatch
+	}
+	namespace, err := a.GetNamespace()
 	if err != nil {
-		return 0, fmt.Errorf("failed to create namespace: %w", err)
+		return nil, err
+	}
+	blobs := make([]*blob.Blob, len(payloads))
+	for i, payload := range payloads {
+		blobs[i], err = blob.NewBlobV0(namespace, payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create blob %d: %w", i, err)
+		}
 	}
-	dataBlob, err := blob.NewBlobV0(namespace, []byte(a.Data))
+
+	client, err := client.NewClient(ctx, a.URL, a.Token)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create blob: %w", err)
+		return nil, fmt.Errorf("failed to initialize Celestia client: %w", err)
 	}
-	sizeOfDataInBytes := len(a.Data)
-	gasLimit := blobtypes.DefaultEstimateGas([]uint32{uint32(sizeOfDataInBytes)})
-	height, err := client.Blob.Submit(ctx, []*blob.Blob{dataBlob}, gasLimit)
+	defer client.Close()
+
+	height, err := client.Blob.Submit(ctx, blobs, a.submitOptions())
 	if err != nil {
-		return 0, fmt.Errorf("failed to submit blob: %w", err)
+		return nil, fmt.Errorf("failed to submit blobs: %w", err)
 	}
 	retrievedBlobs, err := client.Blob.GetAll(ctx, height, []share.Namespace{namespace})
 	if err != nil {
-		return 0, fmt.Errorf("failed to retrieve blob: %w", err)
+		return nil, fmt.Errorf("failed to retrieve blobs: %w", err)
+	}
+	if err := verifyCelestiaBlobs(blobs, retrievedBlobs); err != nil {
+		return nil, err
+	}
+	result := &CelestiaBatchResult{
+		Height:      height,
+		Namespace:   namespace,
+		Commitments: make([]blob.Commitment, len(blobs)),
+		Proofs:      make([]*blob.Proof, len(blobs)),
+	}
+	for i, b := range blobs {
+		result.Commitments[i] = b.Commitment
+		result.Proofs[i], err = GetCelestiaProof(ctx, client, height, namespace, b.Commitment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// GetCelestiaProof fetches the inclusion proof of the blob with [commitment]
+// posted under [namespace] at [height], and has the node check it against
+// the data root of that block.
+func GetCelestiaProof(
+	ctx context.Context,
+	cli *client.Client,
+	height uint64,
+	namespace share.Namespace,
+	commitment blob.Commitment,
+) (*blob.Proof, error) {
+	proof, err := cli.Blob.GetProof(ctx, height, namespace, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get inclusion proof: %w", err)
+	}
+	included, err := cli.Blob.Included(ctx, height, namespace, proof, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check inclusion proof: %w", err)
 	}
-	if !bytes.Equal(dataBlob.Commitment, retrievedBlobs[0].Commitment) {
-		return 0, fmt.Errorf("retrieved blob does not match submitted blob")
+	if !included {
+		return nil, ErrCelestiaBlobNotIncluded
+	}
+	return proof, nil
+}
+
+func (a *SendCelestiaAction) submitOptions() *blob.SubmitOptions {
+	gasPrice := blob.DefaultGasPrice
+	if a.GasPrice > 0 {
+		gasPrice = a.GasPrice
+	}
+	return blob.NewSubmitOptions(
+		blob.WithGasPrice(gasPrice),
+		blob.WithGas(a.GasLimit),
+	)
+}
+
+// verifyCelestiaBlobs checks that every blob of [submitted] is part of
+// [retrieved], which may also hold blobs of other submitters.
+func verifyCelestiaBlobs(submitted []*blob.Blob, retrieved []*blob.Blob) error {
+	byCommitment := make(map[string]*blob.Blob, len(retrieved))
+	for _, b := range retrieved {
+		byCommitment[string(b.Commitment)] = b
+	}
+	for i, b := range submitted {
+		r, ok := byCommitment[string(b.Commitment)]
+		if !ok {
+			return fmt.Errorf("%w: blob %d", ErrCelestiaBlobNotFound, i)
+		}
+		if !bytes.Equal(r.Data, b.Data) {
+			return fmt.Errorf("%w: blob %d", ErrCelestiaBlobMismatch, i)
+		}
+	}
+	return nil
+}
+
+// CelestiaBatcher accumulates small payloads and submits them together with
+// [SendCelestiaAction.ExecuteBatch] once the max batch size would be
+// exceeded or when flushed.
+type CelestiaBatcher struct {
+	action       *SendCelestiaAction
+	maxBatchSize int
+
+	lock     sync.Mutex
+	payloads [][]byte
+	size     int
+}
+
+// NewCelestiaBatcher returns a batcher submitting through [action]. A
+// [maxBatchSize] of 0 selects [DefaultCelestiaMaxBatchSize].
+func NewCelestiaBatcher(action *SendCelestiaAction, maxBatchSize int) *CelestiaBatcher {
+	if maxBatchSize <= 0 {
+		maxBatchSize = DefaultCelestiaMaxBatchSize
+	}
+	return &CelestiaBatcher{
+		action:       action,
+		maxBatchSize: maxBatchSize,
+	}
+}
+
+// Add queues [data]. If the pending batch would grow past the max batch
+// size, it is submitted first and its result is returned.
+func (b *CelestiaBatcher) Add(ctx context.Context, data []byte) (*CelestiaBatchResult, error) {
+	if len(data) == 0 {
+		return nil, ErrEmptyCelestiaPayload
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var (
+		result *CelestiaBatchResult
+		err    error
+	)
+	if len(b.payloads) > 0 && b.size+len(data) > b.maxBatchSize {
+		result, err = b.flush(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	b.payloads = append(b.payloads, data)
+	b.size += len(data)
+	return result, nil
+}
+
+// Flush submits the pending payloads. It returns nil if there are none.
+func (b *CelestiaBatcher) Flush(ctx context.Context) (*CelestiaBatchResult, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if len(b.payloads) == 0 {
+		return nil, nil
+	}
+	return b.flush(ctx)
+}
+
+// Pending returns the number of queued payloads.
+func (b *CelestiaBatcher) Pending() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return len(b.payloads)
+}
+
+func (b *CelestiaBatcher) flush(ctx context.Context) (*CelestiaBatchResult, error) {
+	result, err := b.action.ExecuteBatch(ctx, b.payloads)
+	if err != nil {
+		// Keep the payloads so that the batch can be retried.
+		return nil, err
 	}
-	fmt.Printf("Blob was included at height %d\n", height)
-	return height, nil
+	b.payloads = nil
+	b.size = 0
+	return result, nil
 }
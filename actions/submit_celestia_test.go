@@ -0,0 +1,52 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package actions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyCelestiaBlobs(t *testing.T) {
+	require := require.New(t)
+
+	namespace, err := CelestiaNamespace(CelestiaNamespaceID)
+	require.NoError(err)
+	newBlob := func(data string) *blob.Blob {
+		b, err := blob.NewBlobV0(namespace, []byte(data))
+		require.NoError(err)
+		return b
+	}
+	a, b, other := newBlob("a"), newBlob("b"), newBlob("other")
+
+	require.NoError(verifyCelestiaBlobs([]*blob.Blob{a, b}, []*blob.Blob{other, b, a}))
+	require.ErrorIs(verifyCelestiaBlobs([]*blob.Blob{a, b}, []*blob.Blob{a, other}), ErrCelestiaBlobNotFound)
+
+	tampered := newBlob("c")
+	tampered.Commitment = b.Commitment
+	require.ErrorIs(verifyCelestiaBlobs([]*blob.Blob{a, b}, []*blob.Blob{a, tampered}), ErrCelestiaBlobMismatch)
+}
+
+func TestCelestiaBatcher(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	batcher := NewCelestiaBatcher(&SendCelestiaAction{}, 10)
+	_, err := batcher.Add(ctx, nil)
+	require.ErrorIs(err, ErrEmptyCelestiaPayload)
+
+	result, err := batcher.Add(ctx, make([]byte, 6))
+	require.NoError(err)
+	require.Nil(result)
+	result, err = batcher.Add(ctx, make([]byte, 4))
+	require.NoError(err)
+	require.Nil(result)
+	require.Equal(2, batcher.Pending())
+
+	_, err = (&SendCelestiaAction{}).ExecuteBatch(ctx, nil)
+	require.ErrorIs(err, ErrEmptyCelestiaBatch)
+}
@@ -3,83 +3,134 @@ package actions
 import (
 	"context"
 	"fmt"
-	"os"
-	"time"
+	"slices"
 
 	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
 	"github.com/Layr-Labs/eigenda/clients"
 	"github.com/Layr-Labs/eigenda/core/auth"
+	disperserclient "github.com/Layr-Labs/eigenda/disperser"
 	"github.com/Layr-Labs/eigenda/encoding/utils/codec"
-	"github.com/stretchr/testify/assert"
 )
 
 type sendEigenDAAction struct {
-	DisperserClient *clients.DisperserClient
+	DisperserClient clients.DisperserClient
 	AuthKey         string
+	Config          EigenDADisperserConfig
 }
 
-func NewSendEigenDAAction(authKey string) *sendEigenDAAction {
-	config := clients.NewConfig(
-		"disperser-holesky.eigenda.xyz",
-		"443",
-		time.Second*10,
-		true,            
+func NewSendEigenDAAction(authKey string, config EigenDADisperserConfig) (*sendEigenDAAction, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	clientConfig := clients.NewConfig(
+		config.Host,
+		config.Port,
+		config.Timeout,
+		config.UseTLS,
 	)
 
-	signer := auth.NewSigner(authKey)
-	client := clients.NewDisperserClient(config, signer)
+	signer := auth.NewLocalBlobRequestSigner(authKey)
+	client := clients.NewDisperserClient(clientConfig, signer)
 
 	return &sendEigenDAAction{
 		DisperserClient: client,
 		AuthKey:         authKey,
+		Config:          config,
+	}, nil
+}
+
+var _ EigenDAStatusFetcher = (*sendEigenDAAction)(nil)
+
+// Execute disperses [data] and waits for it to be finalized. The returned
+// certificate is checked against the configured quorums.
+func (e *sendEigenDAAction) Execute(ctx context.Context, data []byte) (*EigenDACertificate, error) {
+	requestID, err := e.Disperse(ctx, data)
+	if err != nil {
+		return nil, err
 	}
+	return WaitForEigenDAFinalization(ctx, e, requestID, e.Config.PollInterval, e.Config.StatusTimeout)
 }
 
-func (e *sendEigenDAAction) Execute(ctx context.Context, data []byte) error {
+// Disperse sends [data] to the disperser and returns the ID of the request
+// without waiting for the blob to be confirmed. The request can be polled
+// with [Status] or an [EigenDATracker].
+func (e *sendEigenDAAction) Disperse(ctx context.Context, data []byte) ([]byte, error) {
 	data = codec.ConvertByPaddingEmptyByte(data)
-	quorums := []uint8{}
-	blobStatus, requestID, err := e.DisperserClient.DisperseBlob(ctx, data, quorums)
-	if err != nil || *blobStatus == disperser.Failed {
-		return fmt.Errorf("error dispersing blob: %v", err)
+	blobStatus, requestID, err := e.DisperserClient.DisperseBlobAuthenticated(ctx, data, e.Config.QuorumIDs())
+	if err != nil {
+		return nil, fmt.Errorf("error dispersing blob: %w", err)
 	}
+	if blobStatus != nil && *blobStatus == disperserclient.Failed {
+		return nil, ErrEigenDADispersalFailed
+	}
+	return requestID, nil
+}
 
-	fmt.Printf("Initial Blob Status: %+v\n", blobStatus)
-	fmt.Printf("Request ID: %s\n", string(requestID))
-
-	statusCtx, cancel := context.WithTimeout(context.Background(), time.Minute*30)
-	defer cancel()
-
-	ticker := time.NewTicker(time.Second * 5)
-	for {
-		select {
-		case <-ticker.C:
-			statusReply, err := e.DisperserClient.GetBlobStatus(statusCtx, requestID)
-			if err != nil {
-				return fmt.Errorf("error getting blob status: %v", err)
-			}
+// Status polls the disperser once for [requestID]. The certificate is set,
+// and checked against the configured quorums, once the blob is confirmed.
+func (e *sendEigenDAAction) Status(ctx context.Context, requestID []byte) (*EigenDAStatusUpdate, error) {
+	reply, err := e.DisperserClient.GetBlobStatus(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting blob status: %w", err)
+	}
 
-			if statusReply.Status == disperser_rpc.BlobStatus_FINALIZED {
-				fmt.Printf("Blob Status is finalized: %s\n", pprint(statusReply))
-				return nil
-			} else if statusReply.Status == disperser_rpc.BlobStatus_FAILED {
-				return fmt.Errorf("error dispersing blob: %v", statusReply.Status)
-			} else {
-				fmt.Printf("Current Blob Status: %s\n", pprint(statusReply))
-			}
-		case <-statusCtx.Done():
-			return fmt.Errorf("timed out waiting for blob to finalize")
+	update := &EigenDAStatusUpdate{RequestID: requestID}
+	switch reply.GetStatus() {
+	case disperser.BlobStatus_PROCESSING, disperser.BlobStatus_DISPERSING:
+		update.Status = EigenDAStatusProcessing
+	case disperser.BlobStatus_CONFIRMED, disperser.BlobStatus_FINALIZED:
+		update.Status = EigenDAStatusConfirmed
+		if reply.GetStatus() == disperser.BlobStatus_FINALIZED {
+			update.Status = EigenDAStatusFinalized
 		}
+		update.Certificate, update.Err = e.certificate(reply)
+		if update.Err != nil {
+			update.Status = EigenDAStatusFailed
+		}
+	case disperser.BlobStatus_FAILED, disperser.BlobStatus_INSUFFICIENT_SIGNATURES:
+		update.Status = EigenDAStatusFailed
+		update.Err = fmt.Errorf("%w: %s", ErrEigenDADispersalFailed, reply.GetStatus())
+	default:
+		update.Status = EigenDAStatusUnknown
 	}
+	return update, nil
 }
 
-func pprint(m proto.Message) string {
-	marshaler := protojson.MarshalOptions{
-		Multiline: true,
-		Indent:    "  ",
+// certificate builds the certificate of a finalized blob and ensures it was
+// dispersed and signed according to the configured quorums.
+func (e *sendEigenDAAction) certificate(reply *disperser.BlobStatusReply) (*EigenDACertificate, error) {
+	header := reply.GetInfo().GetBlobHeader()
+	proof := reply.GetInfo().GetBlobVerificationProof()
+	metadata := proof.GetBatchMetadata()
+	batchHeader := metadata.GetBatchHeader()
+
+	params := header.GetBlobQuorumParams()
+	dispersed := make([]EigenDAQuorum, len(params))
+	for i, param := range params {
+		dispersed[i] = EigenDAQuorum{
+			ID:                    uint8(param.GetQuorumNumber()),
+			AdversaryThreshold:    uint8(param.GetAdversaryThresholdPercentage()),
+			ConfirmationThreshold: uint8(param.GetConfirmationThresholdPercentage()),
+		}
 	}
-	jsonBytes, err := marshaler.Marshal(m)
-	if err != nil {
-		panic("Failed to marshal proto to JSON")
+	if err := e.Config.checkQuorumParams(dispersed); err != nil {
+		return nil, err
 	}
-	return string(jsonBytes)
+	if err := e.Config.checkSignedPercentages(batchHeader.GetQuorumNumbers(), batchHeader.GetQuorumSignedPercentages()); err != nil {
+		return nil, err
+	}
+
+	commitment := header.GetCommitment()
+	return &EigenDACertificate{
+		BatchHeaderHash:         metadata.GetBatchHeaderHash(),
+		BlobIndex:               proof.GetBlobIndex(),
+		BatchRoot:               batchHeader.GetBatchRoot(),
+		ReferenceBlockNumber:    batchHeader.GetReferenceBlockNumber(),
+		ConfirmationBlockNumber: metadata.GetConfirmationBlockNumber(),
+		Commitment:              append(slices.Clone(commitment.GetX()), commitment.GetY()...),
+		DataLength:              header.GetDataLength(),
+		InclusionProof:          proof.GetInclusionProof(),
+		QuorumIndexes:           proof.GetQuorumIndexes(),
+		Quorums:                 newEigenDAQuorumResults(dispersed, batchHeader.GetQuorumNumbers(), batchHeader.GetQuorumSignedPercentages()),
+	}, nil
 }
@@ -2,144 +2,298 @@ package actions
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
-	"crypto/ecdsa"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/holiman/uint256"
 	"github.com/sirupsen/logrus"
-	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 )
 
-const BlobTxDataLimit = 128 * 1024 // Example limit for blob data (128 KB)
-
 type SendBlobAction struct {
 	Client     *ethclient.Client
 	PrivateKey string
-	Data       []byte 
+	Data       []byte
+	// Fees fields left to 0 default to [NewDefaultBlobFeeConfig].
+	Fees BlobFeeConfig
+	// Confirmations is the number of blocks that must be built on top of
+	// the block including a transaction before it is reported.
+	Confirmations uint64
 }
 
-type SendBlobActionResult struct {
-    TransactionHash string `json:"transaction_hash"`
-    Status          string `json:"status"`
-    Units           uint64 `json:"units"`
-    Error           string `json:"error,omitempty"`
+// BlobTxReceipt describes an included blob transaction.
+type BlobTxReceipt struct {
+	TransactionHash string `json:"transaction_hash"`
+	BlockNumber     uint64 `json:"block_number"`
+	BlobGasUsed     uint64 `json:"blob_gas_used"`
+	// BlobGasPrice is in wei.
+	BlobGasPrice string `json:"blob_gas_price"`
+	// BlobHashes are the KZG versioned hashes of the blobs the transaction
+	// carries, in order.
+	BlobHashes []common.Hash `json:"blob_hashes"`
 }
 
-func (s *SendBlobAction) GetTypeID() uint8 {
-    return 1
+type SendBlobActionResult struct {
+	// TransactionHash, BlockNumber, BlobGasUsed and BlobGasPrice describe
+	// the first transaction, which carries the start of the payload.
+	TransactionHash string `json:"transaction_hash"`
+	BlockNumber     uint64 `json:"block_number"`
+	BlobGasUsed     uint64 `json:"blob_gas_used"`
+	BlobGasPrice    string `json:"blob_gas_price"`
+	// Transactions lists the transaction carrying each part of the payload,
+	// in order.
+	Transactions []BlobTxReceipt `json:"transactions"`
+	Blobs        int             `json:"blobs"`
+	Status       string          `json:"status"`
+	Units        uint64          `json:"units"`
+	Error        string          `json:"error,omitempty"`
 }
 
-func (s *SendBlobActionResult) GetTypeID() uint8 {
-    return 2
+func (a *SendBlobAction) ComputeUnits() (uint64, error) {
+	return uint64(len(a.Data) / 1024), nil
 }
 
-func (a *SendBlobAction) ComputeUnits() (uint64, error) {
+// Execute encodes [rawBlob] with [EncodeBlobs] and posts the blobs in as many
+// transactions as needed, using consecutive nonces. Each transaction is
+// waited on, and replaced if needed, until it is included.
+func (a *SendBlobAction) Execute(ctx context.Context, rawBlob []byte) (*SendBlobActionResult, error) {
+	a.Data = rawBlob
+	fees, err := a.Fees.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	a.Fees = fees
+	units, err := a.ComputeUnits()
+	if err != nil {
+		return nil, err
+	}
+	logrus.Infof("Computed units: %d", units)
+	privateKey, err := crypto.HexToECDSA(a.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey := privateKey.Public()
+	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("error casting public key to ECDSA")
+	}
+	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
+	nonce, err := a.Client.PendingNonceAt(ctx, fromAddress)
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := a.Client.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	return uint64(len(a.Data) / 1024), nil 
+	auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	blobs := EncodeBlobs(rawBlob)
+	result := &SendBlobActionResult{
+		Blobs:  len(blobs),
+		Status: "success",
+		Units:  units,
+	}
+	for i, batch := range SplitBlobs(blobs) {
+		sideCar, err := makeSidecar(batch)
+		if err != nil {
+			return nil, err
+		}
+		receipt, err := a.sendWithReplacement(ctx, auth, chainID, nonce+uint64(i), sideCar)
+		if err != nil {
+			return nil, err
+		}
+		receipt, err = a.confirm(ctx, receipt, sideCar)
+		if err != nil {
+			return nil, err
+		}
+		result.Transactions = append(result.Transactions, BlobTxReceipt{
+			TransactionHash: receipt.TxHash.Hex(),
+			BlockNumber:     receipt.BlockNumber.Uint64(),
+			BlobGasUsed:     receipt.BlobGasUsed,
+			BlobGasPrice:    receipt.BlobGasPrice.String(),
+			BlobHashes:      sideCar.BlobHashes(),
+		})
+	}
+	first := result.Transactions[0]
+	result.TransactionHash = first.TransactionHash
+	result.BlockNumber = first.BlockNumber
+	result.BlobGasUsed = first.BlobGasUsed
+	result.BlobGasPrice = first.BlobGasPrice
+	return result, nil
 }
 
-func (a *SendBlobAction) Execute(ctx context.Context, rawBlob []byte) (*SendBlobActionResult, error) {
+// sendWithReplacement sends a transaction carrying [sideCar] at [nonce] and
+// waits for it to be included. A transaction pending for longer than
+// [BlobFeeConfig.ResubmitInterval] is replaced with bumped fees. No
+// transaction is sent past [BlobFeeConfig.MaxFeeCap] or
+// [BlobFeeConfig.MaxBlobFeeCap], including the first one.
+// It returns the receipt of the included transaction.
+func (a *SendBlobAction) sendWithReplacement(
+	ctx context.Context,
+	auth *bind.TransactOpts,
+	chainID *big.Int,
+	nonce uint64,
+	sideCar *types.BlobTxSidecar,
+) (*types.Receipt, error) {
+	fees, err := estimateBlobTxFees(ctx, a.Client, a.Fees)
+	if err != nil {
+		return nil, err
+	}
+	if fees.exceeds(a.Fees) {
+		return nil, fmt.Errorf("%w: nonce %d", ErrMaxFeeExceeded, nonce)
+	}
+	// Any of the sent transactions may be the one that gets included.
+	var sent []common.Hash
+	for {
+		tx := types.NewTx(&types.BlobTx{
+			ChainID:    uint256.MustFromBig(chainID),
+			Nonce:      nonce,
+			GasTipCap:  uint256.MustFromBig(fees.GasTipCap),
+			GasFeeCap:  uint256.MustFromBig(fees.GasFeeCap),
+			Gas:        params.TxGas, // No calldata, the blobs are paid for with blob gas
+			To:         common.HexToAddress("0xb10000000000000000000000000000000000000b"),
+			BlobFeeCap: uint256.MustFromBig(fees.BlobFeeCap),
+			BlobHashes: sideCar.BlobHashes(),
+			Sidecar:    sideCar,
+		})
+
+		signedTx, err := auth.Signer(auth.From, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := a.Client.SendTransaction(ctx, signedTx); err != nil {
+			if len(sent) == 0 {
+				return nil, err
+			}
+			// The previous transaction may have been included meanwhile.
+			logrus.Warnf("Failed to replace blob transaction at nonce %d: %v", nonce, err)
+		} else {
+			logrus.Infof("Sent blob transaction with hash: %s", signedTx.Hash().Hex())
+			sent = append(sent, signedTx.Hash())
+		}
+
+		receipt, err := a.waitForInclusion(ctx, sent)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
 
-    a.Data = rawBlob
-    units, err := a.ComputeUnits()
-    if err != nil {
-        return nil, err
-    }
-    logrus.Infof("Computed units: %d", units)
-    privateKey, err := crypto.HexToECDSA(a.PrivateKey)
-    if err != nil {
-        return nil, err
-    }
-    publicKey := privateKey.Public()
-    publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-    if !ok {
-        return nil, fmt.Errorf("error casting public key to ECDSA")
-    }
-    fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-    nonce, err := a.Client.PendingNonceAt(ctx, fromAddress)
-    if err != nil {
-        return nil, err
-    }
-    gasTipCap, err := a.Client.SuggestGasTipCap(ctx)
-    if err != nil {
-        return nil, err
-    }
-
-    gasFeeCap, err := a.Client.SuggestGasPrice(ctx)
-    if err != nil {
-        return nil, err
-    }
-
-    chainID, err := a.Client.ChainID(ctx)
-    if err != nil {
-        return nil, err
-    }
-
-    blobData := make([]byte, BlobTxDataLimit)
-    copy(blobData, rawBlob)
-
-    blob := kzg4844.Blob(blobData)
-
-    blobs := []kzg4844.Blob{blob}
-    sideCar := makeSidecar(blobs)
-
-    blobHashes := sideCar.BlobHashes()
-
-    blobFeeCap := new(big.Int)
-    blobFeeCap.SetString("1000000000", 10) // 1 Gwei as an example
-
-    tx := types.NewTx(&types.BlobTx{
-        ChainID:   uint256.MustFromBig(chainID),
-        Nonce:     uint64(nonce),
-        GasTipCap: uint256.MustFromBig(gasTipCap),
-        GasFeeCap: uint256.MustFromBig(gasFeeCap),
-        Gas:       uint64(21000), // Example gas limit
-        To:        common.HexToAddress("0xb10000000000000000000000000000000000000b"),
-        BlobFeeCap: uint256.MustFromBig(blobFeeCap),
-        BlobHashes: blobHashes,
-        Sidecar:    sideCar,
-    })
-
-    auth, err := bind.NewKeyedTransactorWithChainID(privateKey, chainID)
-    if err != nil {
-        return nil, err
-    }
-
-    signedTx, err := auth.Signer(auth.From, tx)
-    if err != nil {
-        return nil, err
-    }
-
-    err = a.Client.SendTransaction(ctx, signedTx)
-    if err != nil {
-        return nil, err
-    }
-
-    logrus.Infof("Sent blob transaction with hash: %s", signedTx.Hash().Hex())
-
-    return &SendBlobActionResult{
-        TransactionHash: signedTx.Hash().Hex(),
-        Status:          "success",
-        Units:           units,
-    }, nil
+		fees = fees.bump(a.Fees.BumpPercent)
+		if fees.exceeds(a.Fees) {
+			return nil, fmt.Errorf("%w: nonce %d", ErrMaxFeeExceeded, nonce)
+		}
+	}
 }
 
+// waitForInclusion polls the receipts of [txHashes] for up to
+// [BlobFeeConfig.ResubmitInterval]. It returns a nil receipt if none of them
+// was included in time.
+func (a *SendBlobAction) waitForInclusion(ctx context.Context, txHashes []common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(a.Fees.PollInterval)
+	defer ticker.Stop()
+	timeout := time.After(a.Fees.ResubmitInterval)
+	for {
+		for _, txHash := range txHashes {
+			receipt, err := a.Client.TransactionReceipt(ctx, txHash)
+			if err == nil {
+				return receipt, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				return nil, err
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeout:
+			return nil, nil
+		case <-ticker.C:
+		}
+	}
+}
 
+// confirm waits for [SendBlobAction.Confirmations] blocks on top of the
+// block including [receipt] and checks the included transaction carries the
+// blobs of [sideCar]. It returns the receipt as of the confirming block,
+// which differs from [receipt] if the transaction was reorged into another
+// block.
+func (a *SendBlobAction) confirm(ctx context.Context, receipt *types.Receipt, sideCar *types.BlobTxSidecar) (*types.Receipt, error) {
+	ticker := time.NewTicker(a.Fees.PollInterval)
+	defer ticker.Stop()
+	for {
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			return nil, fmt.Errorf("%w: %s", ErrBlobTxFailed, receipt.TxHash.Hex())
+		}
+		head, err := a.Client.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if head >= receipt.BlockNumber.Uint64()+a.Confirmations {
+			// Fetch the receipt again in case the transaction was reorged.
+			confirmed, err := a.Client.TransactionReceipt(ctx, receipt.TxHash)
+			if errors.Is(err, ethereum.NotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrBlobTxReorged, receipt.TxHash.Hex())
+			}
+			if err != nil {
+				return nil, err
+			}
+			if confirmed.BlockHash == receipt.BlockHash {
+				break
+			}
+			receipt = confirmed
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
 
-func makeSidecar(blobs []kzg4844.Blob) *types.BlobTxSidecar {
+	tx, _, err := a.Client.TransactionByHash(ctx, receipt.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(tx.BlobHashes(), sideCar.BlobHashes()) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobHashMismatch, receipt.TxHash.Hex())
+	}
+	return receipt, nil
+}
+
+func makeSidecar(blobs []kzg4844.Blob) (*types.BlobTxSidecar, error) {
 	var (
 		commitments []kzg4844.Commitment
 		proofs      []kzg4844.Proof
 	)
 
 	for _, blob := range blobs {
-		c, _ := kzg4844.BlobToCommitment(blob)
-		p, _ := kzg4844.ComputeBlobProof(blob, c)
+		c, err := kzg4844.BlobToCommitment(blob)
+		if err != nil {
+			return nil, err
+		}
+		p, err := kzg4844.ComputeBlobProof(blob, c)
+		if err != nil {
+			return nil, err
+		}
 
 		commitments = append(commitments, c)
 		proofs = append(proofs, p)
@@ -149,5 +303,5 @@ func makeSidecar(blobs []kzg4844.Blob) *types.BlobTxSidecar {
 		Blobs:       blobs,
 		Commitments: commitments,
 		Proofs:      proofs,
-	}
+	}, nil
 }
@@ -0,0 +1,341 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// relayer accepts blobs over HTTP, posts them to a DA layer and records the
+// resulting receipts on-chain with a RegisterBlobCommitment transaction.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/cors"
+
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/da"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/api/indexer"
+	"github.com/ava-labs/hypersdk/api/jsonrpc"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+type Config struct {
+	RPCEndpoint string `json:"rpc_endpoint"`
+	Port        string `json:"port"`
+	QueuePath   string `json:"queue_path"`
+	// Retention is how long committed and failed blobs are kept in the queue
+	// and reported by the status endpoint.
+	Retention time.Duration `json:"retention"`
+
+	// PollInterval is how often in-flight blobs are checked on the DA layer
+	// and on-chain.
+	PollInterval time.Duration `json:"poll_interval"`
+	// CommitTimeout is how long to wait for a commitment transaction before
+	// issuing a new one.
+	CommitTimeout time.Duration `json:"commit_timeout"`
+	// MaxAttempts is the number of consecutive errors after which a blob is
+	// marked as failed.
+	MaxAttempts int `json:"max_attempts"`
+	// WaitForFinality delays the on-chain commitment until the blob is
+	// finalized on the DA layer instead of only included.
+	WaitForFinality bool `json:"wait_for_finality"`
+	// Compression is applied to blobs before they are posted: "none",
+	// "zstd" or "brotli".
+	Compression da.Compression `json:"compression"`
+	// ReceiptIndexPath enables deduplication: a blob that was already posted
+	// reuses the receipt recorded there instead of being posted again.
+	ReceiptIndexPath string `json:"receipt_index_path,omitempty"`
+	// EncryptionScope selects the key blobs are encrypted with when
+	// RELAYER_ENCRYPTION_KEY_HEX is set: the relayer address or its Celestia
+	// namespace.
+	EncryptionScope da.KeyScopeKind `json:"encryption_scope"`
+
+	DA da.Config `json:"da"`
+}
+
+func NewDefaultConfig() Config {
+	return Config{
+		Port:            "8766",
+		QueuePath:       "relayer/queue.json",
+		Retention:       24 * time.Hour,
+		PollInterval:    5 * time.Second,
+		CommitTimeout:   time.Minute,
+		MaxAttempts:     5,
+		EncryptionScope: da.KeyScopeAddress,
+		DA:              da.NewDefaultConfig(),
+	}
+}
+
+func loadConfig(path string) (Config, error) {
+	config := NewDefaultConfig()
+	if path == "" {
+		return config, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+func main() {
+	config, err := loadConfig(os.Getenv("RELAYER_CONFIG_FILE"))
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+	if config.RPCEndpoint == "" {
+		log.Fatalf("rpc_endpoint is not set")
+	}
+
+	privBytes, err := hex.DecodeString(os.Getenv("RELAYER_PRIVATE_KEY_HEX"))
+	if err != nil {
+		log.Fatalf("failed to load private key: %v", err)
+	}
+	priv := ed25519.PrivateKey(privBytes)
+	addr := auth.NewED25519Address(priv.PublicKey())
+	log.Printf("Relayer address: %s\n", addr)
+
+	// Keep the data of every relayer in its own Celestia namespace unless
+	// one is configured.
+	if len(config.DA.Celestia.Namespace) == 0 {
+		namespace, err := actions.CelestiaNamespaceFromAddress(addr)
+		if err != nil {
+			log.Fatalf("failed to derive celestia namespace: %v", err)
+		}
+		config.DA.Celestia.Namespace = codec.Bytes(namespace)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stages := pipeline{compression: config.Compression}
+	if keyHex := os.Getenv("RELAYER_ENCRYPTION_KEY_HEX"); keyHex != "" {
+		master, err := hex.DecodeString(keyHex)
+		if err != nil {
+			log.Fatalf("failed to load encryption key: %v", err)
+		}
+		stages.keyring, err = da.NewKeyring(master)
+		if err != nil {
+			log.Fatalf("failed to create keyring: %v", err)
+		}
+		stages.scope = da.AddressScope(addr)
+		if config.EncryptionScope == da.KeyScopeNamespace {
+			stages.scope = da.NamespaceScope(config.DA.Celestia.Namespace)
+		}
+		log.Printf("Encrypting blobs for %s %x\n", stages.scope.Kind, stages.scope.ID)
+	}
+
+	backend, err := da.New(ctx, config.DA)
+	if err != nil {
+		log.Fatalf("failed to create DA backend: %v", err)
+	}
+	backend = stages.wrap(backend)
+	// Payloads larger than a shard are split over the sharding layers, each
+	// shard going through the same stages as other blobs.
+	var sharder *da.Sharder
+	if len(config.DA.Sharding.Layers) > 0 {
+		sharder, err = newSharder(ctx, config.DA, stages)
+		if err != nil {
+			log.Fatalf("failed to create sharder: %v", err)
+		}
+	}
+	if config.ReceiptIndexPath != "" {
+		db, err := pebbledb.New(config.ReceiptIndexPath, nil, logging.NoLog{}, nil)
+		if err != nil {
+			log.Fatalf("failed to open receipt index: %v", err)
+		}
+		defer db.Close()
+		backend = da.NewDeduplicated(backend, da.NewReceiptIndex(db))
+	}
+	q, err := loadQueue(config.QueuePath, config.Retention)
+	if err != nil {
+		log.Fatalf("failed to load queue: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/ext/bc/%s", config.RPCEndpoint, consts.Name)
+	r := &relayer{
+		config:     config,
+		queue:      q,
+		backend:    backend,
+		sharder:    sharder,
+		stages:     stages,
+		factory:    auth.NewED25519Factory(priv),
+		vmCli:      vm.NewJSONRPCClient(url),
+		sdkCli:     jsonrpc.NewJSONRPCClient(url),
+		indexerCli: indexer.NewClient(url),
+	}
+	r.resume(ctx)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/blobs", r.handleSubmit(ctx)).Methods("POST")
+	router.HandleFunc("/blobs/{id}", r.handleStatus).Methods("GET")
+
+	c := cors.New(cors.Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
+		AllowedHeaders: []string{"*"},
+	})
+
+	srv := &http.Server{
+		Addr:         ":" + config.Port,
+		Handler:      c.Handler(router),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	log.Printf("Starting relayer on port %s, posting to %s\n", config.Port, backend.Layer())
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// pipeline holds the stages a payload goes through before being posted.
+// Blobs are compressed before being encrypted, ciphertexts do not compress.
+type pipeline struct {
+	// keyring is nil if blobs are not encrypted.
+	keyring     *da.Keyring
+	scope       da.KeyScope
+	compression da.Compression
+}
+
+// wrap applies the stages of [p] to [backend].
+func (p pipeline) wrap(backend da.DataAvailability) da.DataAvailability {
+	if p.keyring != nil {
+		backend = da.NewEncrypted(backend, p.keyring, p.scope)
+	}
+	if p.compression != da.CompressionNone {
+		backend = da.NewCompressed(backend, p.compression)
+	}
+	return backend
+}
+
+// overhead returns the most bytes the stages of [p] add to a payload.
+func (p pipeline) overhead() uint64 {
+	var overhead uint64
+	if p.keyring != nil {
+		overhead += uint64(da.EnvelopeOverhead(p.scope))
+	}
+	if p.compression != da.CompressionNone {
+		overhead += da.CompressionOverhead
+	}
+	return overhead
+}
+
+// shardingPolicy returns [policy] with its shard size reduced by the
+// overhead of [p], so that shards still fit in [policy.ShardSize] once they
+// went through the stages.
+func shardingPolicy(policy da.ShardingPolicy, p pipeline) (da.ShardingPolicy, error) {
+	overhead := p.overhead()
+	if policy.ShardSize <= overhead {
+		return da.ShardingPolicy{}, fmt.Errorf("%w: shard size %d does not exceed the pipeline overhead of %d bytes", da.ErrInvalidShardingPolicy, policy.ShardSize, overhead)
+	}
+	policy.ShardSize -= overhead
+	return policy, nil
+}
+
+// newSharder returns a sharder over the layers of [config.Sharding], each
+// backend configured like [da.New] would and wrapped by [stages].
+func newSharder(ctx context.Context, config da.Config, stages pipeline) (*da.Sharder, error) {
+	policy, err := shardingPolicy(config.Sharding, stages)
+	if err != nil {
+		return nil, err
+	}
+	backends := make([]da.DataAvailability, len(config.Sharding.Layers))
+	for i, layer := range config.Sharding.Layers {
+		layerConfig := config
+		layerConfig.Layer = layer
+		backend, err := da.New(ctx, layerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s backend: %w", layer, err)
+		}
+		backends[i] = stages.wrap(backend)
+	}
+	return da.NewSharder(backends, policy)
+}
+
+type submitReply struct {
+	ID ids.ID `json:"id"`
+}
+
+// handleSubmit queues the request body for submission. Processing continues
+// under [ctx] after the request returns.
+func (r *relayer) handleSubmit(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		data, err := io.ReadAll(io.LimitReader(req.Body, actions.MaxBlobSize+1))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to read blob: %v", err), http.StatusBadRequest)
+			return
+		}
+		if len(data) == 0 {
+			http.Error(w, "Blob is empty", http.StatusBadRequest)
+			return
+		}
+		if len(data) > actions.MaxBlobSize {
+			http.Error(w, "Blob is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		sharded := r.sharder != nil && uint64(len(data)) > r.sharder.ShardSize()
+		if sharded && r.sharder.Shards(uint64(len(data))) > storage.MaxManifestBlobs {
+			http.Error(w, "Blob needs more shards than a manifest holds", http.StatusRequestEntityTooLarge)
+			return
+		}
+		// Only the first transaction of a payload spread over several
+		// would be located by its on-chain record.
+		posted := len(data) + int(r.stages.overhead())
+		if !sharded && r.backend.Layer() == da.Ethereum && actions.BlobCount(posted) > actions.MaxBlobsPerTx {
+			http.Error(w, "Blob does not fit in one Ethereum transaction, enable sharding to post it", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		id, err := r.queue.add(data)
+		if err != nil {
+			log.Printf("Failed to queue blob: %v\n", err)
+			http.Error(w, fmt.Sprintf("Failed to queue blob: %v", err), http.StatusInternalServerError)
+			return
+		}
+		go r.run(ctx, id)
+
+		writeJSON(w, http.StatusAccepted, &submitReply{ID: id})
+	}
+}
+
+func (r *relayer) handleStatus(w http.ResponseWriter, req *http.Request) {
+	id, err := ids.FromString(mux.Vars(req)["id"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid blob ID: %v", err), http.StatusBadRequest)
+		return
+	}
+	e, ok := r.queue.get(id)
+	if !ok {
+		http.Error(w, "Blob not found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, http.StatusOK, &e)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("Failed to write response: %v\n", err)
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/da"
+)
+
+type entryState string
+
+const (
+	// stateQueued blobs have not been posted to the DA layer yet.
+	stateQueued entryState = "queued"
+	// stateSubmitted blobs were posted and are waiting for inclusion.
+	stateSubmitted entryState = "submitted"
+	// stateAvailable blobs are included and waiting to be committed on-chain.
+	stateAvailable entryState = "available"
+	// stateCommitting blobs have a pending RegisterBlobCommitment transaction.
+	stateCommitting entryState = "committing"
+	stateCommitted  entryState = "committed"
+	stateFailed     entryState = "failed"
+)
+
+func (s entryState) done() bool {
+	return s == stateCommitted || s == stateFailed
+}
+
+type entry struct {
+	ID    ids.ID     `json:"id"`
+	State entryState `json:"state"`
+	// Data is only set in queue files written before payloads were stored
+	// in their own files, it is moved out when the queue is loaded.
+	Data    []byte          `json:"data,omitempty"`
+	Receipt *da.BlobReceipt `json:"receipt,omitempty"`
+	// Manifest is set instead of [Receipt] for payloads split into shards,
+	// with the receipts of the shards posted so far. The shards are
+	// registered on-chain, then grouped by the manifest [ManifestID].
+	Manifest   *da.ShardManifest `json:"manifest,omitempty"`
+	ManifestID ids.ID            `json:"manifest_id"`
+	TxID       ids.ID            `json:"tx_id"`
+	IssuedAt   time.Time         `json:"issued_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	Attempts   int               `json:"attempts"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// queue is the set of blobs handled by the relayer. Every change is written
+// to [path] before it is acknowledged so a restarted relayer resumes
+// in-flight submissions. Payloads are written once to their own file in
+// [payloadDir] and removed when the blob is committed. Done entries are
+// dropped [retention] after their last change.
+type queue struct {
+	path       string
+	payloadDir string
+	retention  time.Duration
+
+	mu      sync.Mutex
+	entries map[ids.ID]*entry
+}
+
+// payloadDir returns the directory payloads of the queue at [path] are
+// stored in.
+func payloadDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".payloads"
+}
+
+func loadQueue(path string, retention time.Duration) (*queue, error) {
+	q := &queue{
+		path:       path,
+		payloadDir: payloadDir(path),
+		retention:  retention,
+		entries:    map[ids.ID]*entry{},
+	}
+	if err := os.MkdirAll(q.payloadDir, 0o700); err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []*entry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Data != nil {
+			if !e.State.done() {
+				if err := q.writePayload(e.ID, e.Data); err != nil {
+					return nil, err
+				}
+			}
+			e.Data = nil
+		}
+		q.entries[e.ID] = e
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.prune(time.Now())
+	return q, q.persist()
+}
+
+func (q *queue) payloadPath(id ids.ID) string {
+	return filepath.Join(q.payloadDir, id.String())
+}
+
+func (q *queue) writePayload(id ids.ID, data []byte) error {
+	path := q.payloadPath(id)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// payload returns the data queued as [id].
+func (q *queue) payload(id ids.ID) ([]byte, error) {
+	return os.ReadFile(q.payloadPath(id))
+}
+
+func (q *queue) removePayload(id ids.ID) error {
+	err := os.Remove(q.payloadPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// add queues [data] for submission.
+func (q *queue) add(data []byte) (ids.ID, error) {
+	var id ids.ID
+	if _, err := rand.Read(id[:]); err != nil {
+		return ids.Empty, err
+	}
+	if err := q.writePayload(id, data); err != nil {
+		return ids.Empty, err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.entries[id] = &entry{
+		ID:        id,
+		State:     stateQueued,
+		UpdatedAt: time.Now(),
+	}
+	if err := q.persist(); err != nil {
+		delete(q.entries, id)
+		return ids.Empty, errors.Join(err, q.removePayload(id))
+	}
+	return id, nil
+}
+
+// get returns a copy of the entry with [id].
+func (q *queue) get(id ids.ID) (entry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e, ok := q.entries[id]
+	if !ok {
+		return entry{}, false
+	}
+	return *e, true
+}
+
+// update applies [f] to the entry with [id] and persists the result.
+func (q *queue) update(id ids.ID, f func(*entry)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	e, ok := q.entries[id]
+	if !ok {
+		return nil
+	}
+	f(e)
+	now := time.Now()
+	e.UpdatedAt = now
+	if e.State == stateCommitted {
+		// The payload is on the DA layer, there is no reason to keep it.
+		if err := q.removePayload(id); err != nil {
+			return err
+		}
+	}
+	q.prune(now)
+	return q.persist()
+}
+
+// prune drops the done entries that did not change for [q.retention]. Must
+// be called with [q.mu] held.
+func (q *queue) prune(now time.Time) {
+	for id, e := range q.entries {
+		if !e.State.done() || now.Sub(e.UpdatedAt) < q.retention {
+			continue
+		}
+		if err := q.removePayload(id); err != nil {
+			// Keep the entry, pruning is tried again on the next change.
+			continue
+		}
+		delete(q.entries, id)
+	}
+}
+
+// inFlight returns the IDs of all entries that are not done yet.
+func (q *queue) inFlight() []ids.ID {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var inFlight []ids.ID
+	for id, e := range q.entries {
+		if !e.State.done() {
+			inFlight = append(inFlight, id)
+		}
+	}
+	return inFlight
+}
+
+// persist atomically replaces the queue file. Must be called with [q.mu]
+// held.
+func (q *queue) persist() error {
+	entries := make([]*entry, 0, len(q.entries))
+	for _, e := range q.entries {
+		entries = append(entries, e)
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return err
+	}
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.path)
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueuePayloads(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := loadQueue(path, time.Hour)
+	require.NoError(err)
+
+	data := []byte("rollup batch")
+	id, err := q.add(data)
+	require.NoError(err)
+	stored, err := q.payload(id)
+	require.NoError(err)
+	require.Equal(data, stored)
+
+	// The queue file only holds the state of the entries.
+	b, err := os.ReadFile(path)
+	require.NoError(err)
+	require.NotContains(string(b), `"data"`)
+
+	q, err = loadQueue(path, time.Hour)
+	require.NoError(err)
+	require.Equal([]ids.ID{id}, q.inFlight())
+
+	require.NoError(q.update(id, func(e *entry) {
+		e.State = stateCommitted
+	}))
+	_, err = q.payload(id)
+	require.ErrorIs(err, os.ErrNotExist)
+	e, ok := q.get(id)
+	require.True(ok)
+	require.Equal(stateCommitted, e.State)
+}
+
+func TestQueuePrune(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := loadQueue(path, time.Hour)
+	require.NoError(err)
+
+	failed, err := q.add([]byte("failed batch"))
+	require.NoError(err)
+	require.NoError(q.update(failed, func(e *entry) {
+		e.State = stateFailed
+	}))
+	queued, err := q.add([]byte("queued batch"))
+	require.NoError(err)
+
+	// Entries are pruned once done for longer than the retention.
+	q.entries[failed].UpdatedAt = time.Now().Add(-2 * time.Hour)
+	q.entries[queued].UpdatedAt = time.Now().Add(-2 * time.Hour)
+	require.NoError(q.update(queued, func(*entry) {}))
+
+	_, ok := q.get(failed)
+	require.False(ok)
+	_, err = q.payload(failed)
+	require.ErrorIs(err, os.ErrNotExist)
+	_, ok = q.get(queued)
+	require.True(ok)
+
+	q, err = loadQueue(path, time.Hour)
+	require.NoError(err)
+	require.Len(q.entries, 1)
+}
+
+func TestQueueLegacyPayloads(t *testing.T) {
+	require := require.New(t)
+
+	path := filepath.Join(t.TempDir(), "queue.json")
+	id := ids.GenerateTestID()
+	b, err := json.Marshal([]*entry{{
+		ID:        id,
+		State:     stateQueued,
+		Data:      []byte("rollup batch"),
+		UpdatedAt: time.Now(),
+	}})
+	require.NoError(err)
+	require.NoError(os.WriteFile(path, b, 0o600))
+
+	q, err := loadQueue(path, time.Hour)
+	require.NoError(err)
+	data, err := q.payload(id)
+	require.NoError(err)
+	require.Equal([]byte("rollup batch"), data)
+
+	b, err = os.ReadFile(path)
+	require.NoError(err)
+	require.NotContains(string(b), `"data"`)
+}
@@ -0,0 +1,436 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/api/indexer"
+	"github.com/ava-labs/hypersdk/api/jsonrpc"
+	"github.com/ava-labs/hypersdk/chain"
+)
+
+// errNotReady is returned by [relayer.step] while an entry is waiting on the
+// DA layer or the chain. It does not count as a failed attempt.
+var errNotReady = errors.New("not ready")
+
+// vmClient is the part of [vm.JSONRPCClient] the relayer uses.
+type vmClient interface {
+	Parser(ctx context.Context) (chain.Parser, error)
+	Blob(ctx context.Context, blobID ids.ID) (*storage.BlobRecord, error)
+	Manifest(ctx context.Context, manifestID ids.ID) (*storage.ManifestRecord, error)
+}
+
+type relayer struct {
+	config  Config
+	queue   *queue
+	backend da.DataAvailability
+	// sharder posts payloads larger than its shard size, if set.
+	sharder *da.Sharder
+	// stages wrap [backend] and the backends of [sharder].
+	stages  pipeline
+	factory chain.AuthFactory
+
+	vmCli      vmClient
+	sdkCli     *jsonrpc.JSONRPCClient
+	indexerCli *indexer.Client
+}
+
+// resume restarts processing of every entry that was in flight when the
+// relayer stopped.
+func (r *relayer) resume(ctx context.Context) {
+	for _, id := range r.queue.inFlight() {
+		go r.run(ctx, id)
+	}
+}
+
+// run drives the entry with [id] until it is committed or failed.
+func (r *relayer) run(ctx context.Context, id ids.ID) {
+	for {
+		e, ok := r.queue.get(id)
+		if !ok || e.State.done() {
+			return
+		}
+		err := r.step(ctx, e)
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, errNotReady):
+		default:
+			log.Printf("blob %s: %s step failed: %v\n", id, e.State, err)
+			if err := r.queue.update(id, func(e *entry) {
+				e.Attempts++
+				e.Error = err.Error()
+				if e.Attempts >= r.config.MaxAttempts {
+					e.State = stateFailed
+				}
+			}); err != nil {
+				log.Printf("blob %s: failed to persist queue: %v\n", id, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.config.PollInterval):
+		}
+	}
+}
+
+// step advances [e] by one state.
+func (r *relayer) step(ctx context.Context, e entry) error {
+	switch e.State {
+	case stateQueued:
+		data, err := r.queue.payload(e.ID)
+		if err != nil {
+			return fmt.Errorf("failed to read blob: %w", err)
+		}
+		if r.sharder != nil && uint64(len(data)) > r.sharder.ShardSize() {
+			return r.submitShards(ctx, e, data)
+		}
+		receipt, err := r.backend.Submit(ctx, data)
+		if err != nil {
+			return fmt.Errorf("failed to submit blob: %w", err)
+		}
+		log.Printf("blob %s: submitted to %s\n", e.ID, receipt.Layer)
+		return r.queue.update(e.ID, func(e *entry) {
+			e.State = stateSubmitted
+			e.Receipt = receipt
+			e.Attempts = 0
+		})
+	case stateSubmitted:
+		if e.Manifest != nil {
+			return r.checkShards(ctx, e)
+		}
+		// GetStatus may record locators in the receipt, such as the
+		// certificate of an EigenDA blob.
+		receipt := *e.Receipt
+		status, err := r.backend.GetStatus(ctx, &receipt)
+		if err != nil {
+			return fmt.Errorf("failed to get blob status: %w", err)
+		}
+		switch {
+		case status == da.StatusFailed:
+			return r.queue.update(e.ID, func(e *entry) {
+				e.State = stateFailed
+				e.Error = "blob failed on the DA layer"
+			})
+		case r.available(status):
+			return r.queue.update(e.ID, func(e *entry) {
+				e.State = stateAvailable
+				e.Receipt = &receipt
+				e.Receipt.Status = status
+				e.Attempts = 0
+			})
+		default:
+			return errNotReady
+		}
+	case stateAvailable:
+		return r.commit(ctx, e)
+	case stateCommitting:
+		return r.checkCommit(ctx, e)
+	default:
+		return nil
+	}
+}
+
+// available returns true if a blob with [status] can be committed on-chain.
+func (r *relayer) available(status da.Status) bool {
+	return status == da.StatusFinalized || (status == da.StatusConfirmed && !r.config.WaitForFinality)
+}
+
+// submitShards posts the shards of [data], the payload of [e]. Only the
+// shards missing from the manifest of a previous attempt are posted. The
+// manifest is persisted even if some shards were not posted.
+func (r *relayer) submitShards(ctx context.Context, e entry, data []byte) error {
+	var (
+		manifest  *da.ShardManifest
+		submitErr error
+	)
+	if e.Manifest == nil {
+		manifest, submitErr = r.sharder.Submit(ctx, data)
+	} else {
+		manifest = cloneManifest(e.Manifest)
+		submitErr = r.sharder.Resubmit(ctx, data, manifest)
+	}
+	if manifest == nil {
+		return fmt.Errorf("failed to shard blob: %w", submitErr)
+	}
+	if err := r.queue.update(e.ID, func(e *entry) {
+		e.Manifest = manifest
+		if submitErr == nil {
+			e.State = stateSubmitted
+			e.Attempts = 0
+		}
+	}); err != nil {
+		return err
+	}
+	if submitErr != nil {
+		return fmt.Errorf("failed to submit shards: %w", submitErr)
+	}
+	log.Printf("blob %s: submitted %d shards\n", e.ID, len(manifest.Shards))
+	return nil
+}
+
+// checkShards moves [e] to available once every one of its shards is.
+func (r *relayer) checkShards(ctx context.Context, e entry) error {
+	manifest := cloneManifest(e.Manifest)
+	blobIDs := make([]ids.ID, len(manifest.Shards))
+	for i, ref := range manifest.Shards {
+		receipt := *ref.Receipt
+		status, err := r.sharder.GetStatus(ctx, &receipt)
+		if err != nil {
+			return fmt.Errorf("failed to get shard %d status: %w", i, err)
+		}
+		if status == da.StatusFailed {
+			return r.queue.update(e.ID, func(e *entry) {
+				e.State = stateFailed
+				e.Error = fmt.Sprintf("shard %d failed on the DA layer", i)
+			})
+		}
+		if !r.available(status) {
+			return errNotReady
+		}
+		receipt.Status = status
+		manifest.Shards[i].Receipt = &receipt
+		action, err := registration(&receipt)
+		if err != nil {
+			return err
+		}
+		blobIDs[i] = storage.BlobID(action.Layer, action.Commitment)
+	}
+	return r.queue.update(e.ID, func(e *entry) {
+		e.State = stateAvailable
+		e.Manifest = manifest
+		e.ManifestID = storage.ManifestID(r.factory.Address(), storage.ManifestRoot(blobIDs))
+		e.Attempts = 0
+	})
+}
+
+// commit issues a transaction with the actions still needed to register [e]
+// on-chain. The transaction ID is persisted before the transaction is
+// submitted so that a restart never loses track of it. No transaction is
+// issued if everything is already registered, as when a deduplicated payload
+// was committed by another entry or an expired transaction landed after all.
+func (r *relayer) commit(ctx context.Context, e entry) error {
+	pending, err := r.pending(ctx, e)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return r.markCommitted(e.ID)
+	}
+	parser, err := r.vmCli.Parser(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get parser: %w", err)
+	}
+	// The shards of a large payload may take several transactions.
+	maxActions := int(parser.Rules(time.Now().UnixMilli()).GetMaxActionsPerTx())
+	if len(pending) > maxActions {
+		pending = pending[:maxActions]
+	}
+	submit, tx, _, err := r.sdkCli.GenerateTransaction(
+		ctx,
+		parser,
+		pending,
+		r.factory,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to generate transaction: %w", err)
+	}
+	if err := r.queue.update(e.ID, func(e *entry) {
+		e.State = stateCommitting
+		e.TxID = tx.ID()
+		e.IssuedAt = time.Now()
+	}); err != nil {
+		return err
+	}
+	if err := submit(ctx); err != nil {
+		return fmt.Errorf("failed to submit transaction: %w", err)
+	}
+	log.Printf("blob %s: issued commitment transaction %s\n", e.ID, tx.ID())
+	return nil
+}
+
+func (r *relayer) checkCommit(ctx context.Context, e entry) error {
+	resp, found, err := r.indexerCli.GetTx(ctx, e.TxID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction: %w", err)
+	}
+	switch {
+	case found && resp.Success && e.Manifest != nil:
+		// The manifest may still need to be registered.
+		return r.queue.update(e.ID, func(e *entry) {
+			e.State = stateAvailable
+			e.Error = ""
+		})
+	case found && resp.Success:
+		log.Printf("blob %s: committed in transaction %s\n", e.ID, e.TxID)
+		return r.queue.update(e.ID, func(e *entry) {
+			e.State = stateCommitted
+			e.Error = ""
+		})
+	case found:
+		// The transaction may have lost a race against another registering
+		// the same blob.
+		pending, err := r.pending(ctx, e)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return r.markCommitted(e.ID)
+		}
+		if e.Manifest != nil {
+			// Retry the registrations that are still missing.
+			if err := r.queue.update(e.ID, func(e *entry) {
+				e.State = stateAvailable
+			}); err != nil {
+				return err
+			}
+			return fmt.Errorf("commitment transaction failed: %s", resp.ErrorStr)
+		}
+		return r.queue.update(e.ID, func(e *entry) {
+			e.State = stateFailed
+			e.Error = fmt.Sprintf("commitment transaction failed: %s", resp.ErrorStr)
+		})
+	case time.Since(e.IssuedAt) > r.config.CommitTimeout:
+		// The transaction expired (or was never submitted before a
+		// restart), issue a new one.
+		return r.queue.update(e.ID, func(e *entry) {
+			e.State = stateAvailable
+		})
+	default:
+		return errNotReady
+	}
+}
+
+// pending returns the actions still needed to register [e] on-chain: the
+// commitment of its blob, or the commitments of its shards followed by its
+// manifest.
+func (r *relayer) pending(ctx context.Context, e entry) ([]chain.Action, error) {
+	if e.Manifest == nil {
+		action, err := registration(e.Receipt)
+		if err != nil {
+			return nil, err
+		}
+		registered, err := r.registered(ctx, action)
+		if err != nil || registered {
+			return nil, err
+		}
+		return []chain.Action{action}, nil
+	}
+
+	var (
+		pending []chain.Action
+		blobIDs = make([]ids.ID, len(e.Manifest.Shards))
+	)
+	for i, ref := range e.Manifest.Shards {
+		action, err := registration(ref.Receipt)
+		if err != nil {
+			return nil, fmt.Errorf("shard %d: %w", i, err)
+		}
+		blobIDs[i] = storage.BlobID(action.Layer, action.Commitment)
+		registered, err := r.registered(ctx, action)
+		if err != nil {
+			return nil, err
+		}
+		if !registered {
+			pending = append(pending, action)
+		}
+	}
+	if len(pending) > 0 {
+		return pending, nil
+	}
+	_, err := r.vmCli.Manifest(ctx, e.ManifestID)
+	switch {
+	case errors.Is(err, vm.ErrManifestNotFound):
+		return []chain.Action{&actions.RegisterBlobManifest{Blobs: blobIDs}}, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get manifest: %w", err)
+	default:
+		return nil, nil
+	}
+}
+
+// registered returns true if the blob of [action] is already registered
+// on-chain.
+func (r *relayer) registered(ctx context.Context, action *actions.RegisterBlobCommitment) (bool, error) {
+	_, err := r.vmCli.Blob(ctx, storage.BlobID(action.Layer, action.Commitment))
+	switch {
+	case errors.Is(err, vm.ErrBlobNotFound):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to get blob: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+// markCommitted marks the entry with [id] as committed once everything it
+// needs is registered on-chain.
+func (r *relayer) markCommitted(id ids.ID) error {
+	log.Printf("blob %s: registered on-chain\n", id)
+	return r.queue.update(id, func(e *entry) {
+		e.State = stateCommitted
+		e.Error = ""
+	})
+}
+
+// cloneManifest copies [manifest] so that its shards can be updated without
+// changing the queue entry.
+func cloneManifest(manifest *da.ShardManifest) *da.ShardManifest {
+	clone := *manifest
+	clone.Shards = slices.Clone(manifest.Shards)
+	return &clone
+}
+
+// registration returns the action registering [receipt] on-chain.
+func registration(receipt *da.BlobReceipt) (*actions.RegisterBlobCommitment, error) {
+	locator, err := receipt.Locator()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate blob: %w", err)
+	}
+	return &actions.RegisterBlobCommitment{
+		Layer:      uint8(receipt.Layer),
+		Commitment: commitment(receipt),
+		Height:     receipt.Height,
+		Namespace:  receipt.Namespace,
+		Size:       receipt.Size,
+		Ciphertext: ciphertext(receipt),
+		Locator:    locator,
+	}, nil
+}
+
+// commitment returns the layer-specific commitment recorded on-chain for
+// [receipt].
+func commitment(receipt *da.BlobReceipt) []byte {
+	switch {
+	case len(receipt.Commitment) > 0:
+		return receipt.Commitment
+	case len(receipt.TxHash) > 0:
+		return receipt.TxHash
+	default:
+		return receipt.Digest[:]
+	}
+}
+
+// ciphertext returns the digest of the encrypted payload recorded on-chain
+// for [receipt], or nil if the blob was not encrypted.
+func ciphertext(receipt *da.BlobReceipt) []byte {
+	if receipt.Encoding == nil || receipt.Encoding.Encryption == nil {
+		return nil
+	}
+	return receipt.Digest[:]
+}
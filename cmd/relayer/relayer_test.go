@@ -0,0 +1,363 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk-starter-kit/vm"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+var (
+	errNoParser    = errors.New("no parser")
+	errUnavailable = errors.New("layer unavailable")
+)
+
+// chainClient serves the blobs and manifests registered in [blobs] and
+// [manifests].
+type chainClient struct {
+	blobs     map[ids.ID]*storage.BlobRecord
+	manifests map[ids.ID]*storage.ManifestRecord
+}
+
+func newChainClient() *chainClient {
+	return &chainClient{
+		blobs:     make(map[ids.ID]*storage.BlobRecord),
+		manifests: make(map[ids.ID]*storage.ManifestRecord),
+	}
+}
+
+func (*chainClient) Parser(context.Context) (chain.Parser, error) {
+	return nil, errNoParser
+}
+
+func (c *chainClient) Blob(_ context.Context, blobID ids.ID) (*storage.BlobRecord, error) {
+	record, ok := c.blobs[blobID]
+	if !ok {
+		return nil, vm.ErrBlobNotFound
+	}
+	return record, nil
+}
+
+func (c *chainClient) Manifest(_ context.Context, manifestID ids.ID) (*storage.ManifestRecord, error) {
+	record, ok := c.manifests[manifestID]
+	if !ok {
+		return nil, vm.ErrManifestNotFound
+	}
+	return record, nil
+}
+
+// celestiaBackend includes every blob it is given at once, after failing
+// [failures] times. The size of every included blob is kept in [sizes].
+type celestiaBackend struct {
+	failures int
+
+	lock        sync.Mutex
+	submissions int
+	sizes       []int
+}
+
+func (*celestiaBackend) Layer() da.Layer {
+	return da.Celestia
+}
+
+func (c *celestiaBackend) Submit(_ context.Context, data []byte) (*da.BlobReceipt, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.submissions++
+	if c.submissions <= c.failures {
+		return nil, errUnavailable
+	}
+	c.sizes = append(c.sizes, len(data))
+	digest := sha256.Sum256(data)
+	return &da.BlobReceipt{
+		Layer:      da.Celestia,
+		Status:     da.StatusFinalized,
+		Digest:     digest,
+		Size:       uint64(len(data)),
+		Height:     10,
+		Commitment: digest[:],
+	}, nil
+}
+
+func (*celestiaBackend) GetStatus(context.Context, *da.BlobReceipt) (da.Status, error) {
+	return da.StatusFinalized, nil
+}
+
+func (*celestiaBackend) Retrieve(context.Context, *da.BlobReceipt) ([]byte, error) {
+	return nil, da.ErrRetrieveNotSupported
+}
+
+func (*celestiaBackend) Verify(context.Context, *da.BlobReceipt) error {
+	return nil
+}
+
+// advance steps the entry with [id] until it is done or a step fails. A
+// step fails at the latest when a transaction should be issued, as
+// [chainClient] has no parser.
+func advance(t *testing.T, r *relayer, id ids.ID) (entry, error) {
+	for {
+		e, ok := r.queue.get(id)
+		require.True(t, ok)
+		if e.State.done() {
+			return e, nil
+		}
+		if err := r.step(context.Background(), e); err != nil {
+			e, _ = r.queue.get(id)
+			return e, err
+		}
+	}
+}
+
+func TestRelayerDuplicateSubmission(t *testing.T) {
+	require := require.New(t)
+
+	q, err := loadQueue(filepath.Join(t.TempDir(), "queue.json"), time.Hour)
+	require.NoError(err)
+	backend := &celestiaBackend{}
+	chainCli := newChainClient()
+	r := &relayer{
+		config:  NewDefaultConfig(),
+		queue:   q,
+		backend: da.NewDeduplicated(backend, da.NewReceiptIndex(memdb.New())),
+		vmCli:   chainCli,
+	}
+
+	data := []byte("rollup batch")
+	first, err := q.add(data)
+	require.NoError(err)
+	e, err := advance(t, r, first)
+	// The commitment transaction would be built next.
+	require.ErrorIs(err, errNoParser)
+	require.Equal(stateAvailable, e.State)
+
+	// The first entry gets registered, and the same payload is queued again.
+	action, err := registration(e.Receipt)
+	require.NoError(err)
+	chainCli.blobs[storage.BlobID(action.Layer, action.Commitment)] = &storage.BlobRecord{}
+	second, err := q.add(data)
+	require.NoError(err)
+
+	// The receipt is reused and no duplicate registration is attempted.
+	e, err = advance(t, r, second)
+	require.NoError(err)
+	require.Equal(stateCommitted, e.State)
+	require.Empty(e.Error)
+	require.Equal(1, backend.submissions)
+
+	// The first entry is not registered again either, as when its
+	// transaction lands after the commit timeout.
+	e, err = advance(t, r, first)
+	require.NoError(err)
+	require.Equal(stateCommitted, e.State)
+}
+
+func TestRelayerShardedSubmission(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	q, err := loadQueue(filepath.Join(t.TempDir(), "queue.json"), time.Hour)
+	require.NoError(err)
+	priv, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	shards := &celestiaBackend{failures: 1}
+	sharder, err := da.NewSharder([]da.DataAvailability{shards}, da.ShardingPolicy{ShardSize: 10, ParityRatio: 0.5})
+	require.NoError(err)
+	chainCli := newChainClient()
+	r := &relayer{
+		config:  NewDefaultConfig(),
+		queue:   q,
+		backend: &celestiaBackend{},
+		sharder: sharder,
+		factory: auth.NewED25519Factory(priv),
+		vmCli:   chainCli,
+	}
+
+	// 3 data shards and 2 parity shards, one of which is not posted at
+	// first.
+	data := []byte("a rollup batch larger than a shard")[:30]
+	id, err := q.add(data)
+	require.NoError(err)
+	e, err := advance(t, r, id)
+	require.ErrorIs(err, da.ErrShardNotPosted)
+	require.Equal(stateQueued, e.State)
+	require.Len(e.Manifest.Shards, 5)
+	require.Equal(5, shards.submissions)
+
+	// Only the missing shard is posted again.
+	e, err = advance(t, r, id)
+	require.ErrorIs(err, errNoParser)
+	require.Equal(stateAvailable, e.State)
+	require.Equal(6, shards.submissions)
+
+	// Every shard is registered first.
+	pending, err := r.pending(ctx, e)
+	require.NoError(err)
+	require.Len(pending, 5)
+	blobIDs := make([]ids.ID, len(pending))
+	for i, action := range pending {
+		registration := action.(*actions.RegisterBlobCommitment)
+		require.Equal(e.Manifest.Shards[i].Receipt.Commitment, codec.Bytes(registration.Commitment))
+		blobIDs[i] = storage.BlobID(registration.Layer, registration.Commitment)
+	}
+	for _, blobID := range blobIDs[:4] {
+		chainCli.blobs[blobID] = &storage.BlobRecord{}
+	}
+	pending, err = r.pending(ctx, e)
+	require.NoError(err)
+	require.Len(pending, 1)
+
+	// Then the manifest grouping them, in order.
+	chainCli.blobs[blobIDs[4]] = &storage.BlobRecord{}
+	pending, err = r.pending(ctx, e)
+	require.NoError(err)
+	require.Equal([]chain.Action{&actions.RegisterBlobManifest{Blobs: blobIDs}}, pending)
+	manifestID := storage.ManifestID(r.factory.Address(), storage.ManifestRoot(blobIDs))
+	require.Equal(manifestID, e.ManifestID)
+
+	chainCli.manifests[manifestID] = &storage.ManifestRecord{}
+	e, err = advance(t, r, id)
+	require.NoError(err)
+	require.Equal(stateCommitted, e.State)
+}
+
+func TestShardingPolicyOverhead(t *testing.T) {
+	require := require.New(t)
+
+	master := make([]byte, da.KeySize)
+	_, err := rand.Read(master)
+	require.NoError(err)
+	keyring, err := da.NewKeyring(master)
+	require.NoError(err)
+	stages := pipeline{
+		keyring:     keyring,
+		scope:       da.AddressScope(codectest.NewRandomAddress()),
+		compression: da.CompressionZstd,
+	}
+
+	// A shard of the default size no longer fits in one blob once it went
+	// through the stages.
+	require.Equal(2, actions.BlobCount(da.DefaultShardSize+int(stages.overhead())))
+
+	policy, err := shardingPolicy(da.NewDefaultShardingPolicy(), stages)
+	require.NoError(err)
+	backend := &celestiaBackend{}
+	sharder, err := da.NewSharder([]da.DataAvailability{stages.wrap(backend)}, policy)
+	require.NoError(err)
+
+	// Random data does not compress, so every shard carries the whole
+	// overhead.
+	data := make([]byte, 3*policy.ShardSize)
+	_, err = rand.Read(data)
+	require.NoError(err)
+	manifest, err := sharder.Submit(context.Background(), data)
+	require.NoError(err)
+	require.Len(backend.sizes, len(manifest.Shards))
+	for _, size := range backend.sizes {
+		require.Equal(1, actions.BlobCount(size))
+	}
+
+	_, err = shardingPolicy(da.ShardingPolicy{ShardSize: stages.overhead()}, stages)
+	require.ErrorIs(err, da.ErrInvalidShardingPolicy)
+}
+
+// ethereumBackend posts like [celestiaBackend] but reports Ethereum.
+type ethereumBackend struct {
+	celestiaBackend
+}
+
+func (*ethereumBackend) Layer() da.Layer {
+	return da.Ethereum
+}
+
+func TestSubmitEthereumSize(t *testing.T) {
+	require := require.New(t)
+
+	q, err := loadQueue(filepath.Join(t.TempDir(), "queue.json"), time.Hour)
+	require.NoError(err)
+	r := &relayer{
+		config:  NewDefaultConfig(),
+		queue:   q,
+		backend: &ethereumBackend{},
+		vmCli:   newChainClient(),
+	}
+
+	// The payload spans two transactions, whose second one could not be
+	// located from the on-chain record.
+	data := make([]byte, actions.MaxBlobsPerTx*actions.UsableBytesPerBlob)
+	rec := httptest.NewRecorder()
+	r.handleSubmit(context.Background())(rec, httptest.NewRequest(http.MethodPost, "/blobs", bytes.NewReader(data)))
+	require.Equal(http.StatusRequestEntityTooLarge, rec.Code)
+	require.Empty(q.inFlight())
+}
+
+func TestRegistrationLocator(t *testing.T) {
+	require := require.New(t)
+
+	batchHeaderHash := bytes.Repeat([]byte{0x01}, 32)
+	action, err := registration(&da.BlobReceipt{
+		Layer:      da.EigenDA,
+		Size:       100,
+		Commitment: []byte{0x02},
+		Certificate: &actions.EigenDACertificate{
+			BatchHeaderHash:      batchHeaderHash,
+			BlobIndex:            7,
+			ReferenceBlockNumber: 1_000,
+		},
+	})
+	require.NoError(err)
+	require.Equal([]byte{0x02}, action.Commitment)
+	locator := binary.BigEndian.AppendUint32(bytes.Clone(batchHeaderHash), 7)
+	locator = binary.BigEndian.AppendUint32(locator, 1_000)
+	require.Equal(locator, action.Locator)
+
+	blockHash := bytes.Repeat([]byte{0x03}, 32)
+	action, err = registration(&da.BlobReceipt{
+		Layer:     da.Avail,
+		Size:      100,
+		Height:    10,
+		TxHash:    bytes.Repeat([]byte{0x04}, 32),
+		BlockHash: blockHash,
+		Index:     2,
+	})
+	require.NoError(err)
+	require.Equal(uint64(10), action.Height)
+	require.Equal(binary.BigEndian.AppendUint32(bytes.Clone(blockHash), 2), action.Locator)
+
+	action, err = registration(&da.BlobReceipt{
+		Layer:      da.Celestia,
+		Size:       100,
+		Commitment: []byte{0x05},
+	})
+	require.NoError(err)
+	require.Empty(action.Locator)
+
+	// An EigenDA blob cannot be registered before its certificate is issued.
+	_, err = registration(&da.BlobReceipt{Layer: da.EigenDA, Size: 100})
+	require.ErrorIs(err, da.ErrInvalidReceipt)
+}
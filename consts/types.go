@@ -5,5 +5,15 @@ package consts
 
 const (
 	// Action TypeIDs
-	TransferID uint8 = 0
+	TransferID               uint8 = 0
+	RegisterBlobCommitmentID uint8 = 1
+	RegisterBlobManifestID   uint8 = 2
+)
+
+const (
+	// DA layer IDs
+	EthereumLayerID uint8 = 1
+	CelestiaLayerID uint8 = 2
+	AvailLayerID    uint8 = 3
+	EigenDALayerID  uint8 = 4
 )
@@ -0,0 +1,134 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/availproject/avail-go-sdk/src/sdk"
+	"github.com/availproject/avail-go-sdk/src/sdk/types"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+var (
+	_ DataAvailability = (*availDA)(nil)
+	_ CostEstimator    = (*availDA)(nil)
+)
+
+type availDA struct {
+	config AvailConfig
+	// api is shared by the status and retrieval queries, so that polling
+	// does not open a connection each time.
+	api *sdk.SubstrateAPI
+}
+
+// NewAvail returns a backend that submits data extrinsics to Avail.
+func NewAvail(config AvailConfig) (DataAvailability, error) {
+	api, err := sdk.NewSDK(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to avail node: %w", err)
+	}
+	return &availDA{
+		config: config,
+		api:    api,
+	}, nil
+}
+
+func (*availDA) Layer() Layer {
+	return Avail
+}
+
+func (a *availDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	action := &actions.SendAvailAction{
+		Seed:                a.config.Seed,
+		Data:                string(data),
+		NetworkURL:          a.config.URL,
+		AppID:               a.config.AppID,
+		AppKey:              a.config.AppKey,
+		WaitForFinalization: a.config.WaitForFinalization,
+	}
+	result, err := action.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	block, err := types.NewHashFromHexString(result.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := types.NewHashFromHexString(result.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	receipt := newReceipt(Avail, data)
+	receipt.Status = StatusConfirmed
+	if result.Finalized {
+		receipt.Status = StatusFinalized
+	}
+	receipt.Height = uint64(result.BlockNumber)
+	receipt.Index = result.ExtrinsicIndex
+	receipt.BlockHash = block[:]
+	receipt.TxHash = tx[:]
+	// The app ID plays the role of a namespace.
+	receipt.Namespace = binary.BigEndian.AppendUint32(nil, result.AppID)
+	return receipt, nil
+}
+
+func (a *availDA) GetStatus(_ context.Context, receipt *BlobReceipt) (Status, error) {
+	if receipt.Layer != Avail {
+		return StatusUnknown, ErrLayerMismatch
+	}
+	header, err := a.api.RPC.Chain.GetHeader(types.NewHash(receipt.BlockHash))
+	if err != nil {
+		return StatusUnknown, err
+	}
+	finalizedHash, err := a.api.RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return StatusUnknown, err
+	}
+	finalized, err := a.api.RPC.Chain.GetHeader(finalizedHash)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if header.Number <= finalized.Number {
+		return StatusFinalized, nil
+	}
+	return StatusConfirmed, nil
+}
+
+// Retrieve reads the data extrinsic back and checks it against the data root
+// of its block.
+func (a *availDA) Retrieve(ctx context.Context, receipt *BlobReceipt) ([]byte, error) {
+	if receipt.Layer != Avail {
+		return nil, ErrLayerMismatch
+	}
+	// Submit stores the app ID as the namespace.
+	if len(receipt.Namespace) != 4 {
+		return nil, ErrInvalidReceipt
+	}
+	return actions.RetrieveAvailData(
+		ctx,
+		a.api,
+		types.NewHash(receipt.BlockHash).Hex(),
+		receipt.Index,
+		binary.BigEndian.Uint32(receipt.Namespace),
+	)
+}
+
+func (a *availDA) Verify(ctx context.Context, receipt *BlobReceipt) error {
+	data, err := a.Retrieve(ctx, receipt)
+	if err != nil {
+		return err
+	}
+	return checkDigest(receipt, data)
+}
+
+// EstimateCost returns the length fee of the data extrinsic, see
+// [actions.EstimateAvailFee].
+func (a *availDA) EstimateCost(ctx context.Context, size uint64) (*big.Int, error) {
+	return actions.EstimateAvailFee(ctx, a.config.URL, int(size))
+}
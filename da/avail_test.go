@@ -0,0 +1,134 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+)
+
+// availTestSeed is the development account of the Avail SDK examples.
+const availTestSeed = "//Alice"
+
+func TestAvailSubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewAvail(t)
+	backend, err := NewAvail(AvailConfig{
+		URL:   fake.URL,
+		Seed:  availTestSeed,
+		AppID: 7,
+	})
+	require.NoError(err)
+
+	data := []byte("rollup batch")
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(StatusConfirmed, receipt.Status)
+	require.Equal(binary.BigEndian.AppendUint32(nil, 7), []byte(receipt.Namespace))
+
+	status, err := backend.GetStatus(ctx, receipt)
+	require.NoError(err)
+	require.Equal(StatusConfirmed, status)
+	fake.BuildBlock()
+	status, err = backend.GetStatus(ctx, receipt)
+	require.NoError(err)
+	require.Equal(StatusFinalized, status)
+
+	retrieved, err := backend.Retrieve(ctx, receipt)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+	require.NoError(backend.Verify(ctx, receipt))
+
+	otherApp := *receipt
+	otherApp.Namespace = binary.BigEndian.AppendUint32(nil, 8)
+	_, err = backend.Retrieve(ctx, &otherApp)
+	require.ErrorIs(err, actions.ErrAvailAppIDMismatch)
+
+	otherExtrinsic := *receipt
+	otherExtrinsic.Index++
+	_, err = backend.Retrieve(ctx, &otherExtrinsic)
+	require.ErrorIs(err, actions.ErrAvailExtrinsicIndex)
+
+	otherData := *receipt
+	otherData.Digest[0] ^= 0xFF
+	require.ErrorIs(backend.Verify(ctx, &otherData), ErrDigestMismatch)
+}
+
+func TestAvailAppKey(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewAvail(t)
+	backend, err := NewAvail(AvailConfig{
+		URL:    fake.URL,
+		Seed:   availTestSeed,
+		AppKey: "rollup",
+	})
+	require.NoError(err)
+
+	first, err := backend.Submit(ctx, []byte("first batch"))
+	require.NoError(err)
+	appID, ok := fake.AppID("rollup")
+	require.True(ok)
+	require.Equal(binary.BigEndian.AppendUint32(nil, appID), []byte(first.Namespace))
+
+	// The key is registered once.
+	second, err := backend.Submit(ctx, []byte("second batch"))
+	require.NoError(err)
+	require.Equal(first.Namespace, second.Namespace)
+	require.Equal(first.Height+1, second.Height)
+
+	retrieved, err := backend.Retrieve(ctx, second)
+	require.NoError(err)
+	require.Equal([]byte("second batch"), retrieved)
+}
+
+func TestAvailWaitForFinalization(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewAvail(t)
+	backend, err := NewAvail(AvailConfig{
+		URL:                 fake.URL,
+		Seed:                availTestSeed,
+		AppID:               1,
+		WaitForFinalization: true,
+	})
+	require.NoError(err)
+
+	// Keep building blocks so that the submitted one gets finalized.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fake.BuildBlock()
+			}
+		}
+	}()
+
+	data := []byte("rollup batch")
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(StatusFinalized, receipt.Status)
+
+	status, err := backend.GetStatus(ctx, receipt)
+	require.NoError(err)
+	require.Equal(StatusFinalized, status)
+	require.NoError(backend.Verify(ctx, receipt))
+}
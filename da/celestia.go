@@ -0,0 +1,219 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+
+	client "github.com/celestiaorg/celestia-openrpc"
+)
+
+var (
+	_ DataAvailability = (*celestiaDA)(nil)
+	_ HealthChecker    = (*celestiaDA)(nil)
+	_ Pricer           = (*celestiaDA)(nil)
+	_ CostEstimator    = (*celestiaDA)(nil)
+)
+
+type celestiaDA struct {
+	url       string
+	token     string
+	namespace []byte
+	gasPrice  float64
+}
+
+// NewCelestia returns a backend that submits blobs through a Celestia node
+// under [namespace], see [actions.CelestiaNamespace]. An empty [namespace]
+// selects [actions.CelestiaNamespaceID]. A [gasPrice] of 0 uses the minimum
+// gas price of the node.
+func NewCelestia(url string, token string, namespace []byte, gasPrice float64) DataAvailability {
+	return &celestiaDA{
+		url:       url,
+		token:     token,
+		namespace: namespace,
+		gasPrice:  gasPrice,
+	}
+}
+
+func (*celestiaDA) Layer() Layer {
+	return Celestia
+}
+
+func (c *celestiaDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	action := &actions.SendCelestiaAction{
+		URL:       c.url,
+		Token:     c.token,
+		Namespace: c.namespace,
+		GasPrice:  c.gasPrice,
+	}
+	result, err := action.ExecuteBatch(ctx, [][]byte{data})
+	if err != nil {
+		return nil, err
+	}
+	receipt := newReceipt(Celestia, data)
+	// ExecuteBatch only returns once the blob was read back at its height.
+	receipt.Status = StatusFinalized
+	receipt.Height = result.Height
+	receipt.Namespace = codec.Bytes(result.Namespace)
+	receipt.Commitment = codec.Bytes(result.Commitments[0])
+	return receipt, nil
+}
+
+func (c *celestiaDA) GetStatus(ctx context.Context, receipt *BlobReceipt) (Status, error) {
+	if _, err := c.get(ctx, receipt); err != nil {
+		return StatusUnknown, err
+	}
+	// Celestia has single slot finality.
+	return StatusFinalized, nil
+}
+
+func (c *celestiaDA) Retrieve(ctx context.Context, receipt *BlobReceipt) ([]byte, error) {
+	dataBlob, err := c.get(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return dataBlob.Data, nil
+}
+
+func (c *celestiaDA) Verify(ctx context.Context, receipt *BlobReceipt) error {
+	if receipt.Layer != Celestia {
+		return ErrLayerMismatch
+	}
+	verifier := NewCelestiaVerifier(c.url, c.token)
+	if _, err := verifier.Proof(ctx, receipt.Height, receipt.Namespace, receipt.Commitment); err != nil {
+		return err
+	}
+	data, err := c.Retrieve(ctx, receipt)
+	if err != nil {
+		return err
+	}
+	return checkDigest(receipt, data)
+}
+
+// HealthCheck fails if the node is not reachable or not ready.
+func (c *celestiaDA) HealthCheck(ctx context.Context) error {
+	cli, err := client.NewClient(ctx, c.url, c.token)
+	if err != nil {
+		return err
+	}
+	defer cli.Close()
+
+	ready, err := cli.Node.Ready(ctx)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		return ErrSyncing
+	}
+	return nil
+}
+
+// Price returns the gas price submissions pay, in utia per gas. If none is
+// configured, this is the default minimum gas price of the nodes.
+func (c *celestiaDA) Price(context.Context) (float64, error) {
+	return celestiaGasPrice(c.gasPrice), nil
+}
+
+// EstimateCost prices the gas of a PayForBlobs transaction at [c.Price].
+func (c *celestiaDA) EstimateCost(ctx context.Context, size uint64) (*big.Int, error) {
+	price, err := c.Price(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return celestiaCost(size, price), nil
+}
+
+// celestiaGasPrice returns [gasPrice], or the minimum gas price if it is 0.
+func celestiaGasPrice(gasPrice float64) float64 {
+	if gasPrice > 0 {
+		return gasPrice
+	}
+	return appconsts.DefaultMinGasPrice
+}
+
+// celestiaCost returns the fee, in utia, of posting [size] bytes at [price].
+func celestiaCost(size uint64, price float64) *big.Int {
+	gas := new(big.Float).SetUint64(actions.EstimateCelestiaGas(int(size)))
+	fee, accuracy := gas.Mul(gas, big.NewFloat(price)).Int(nil)
+	// Fees are rounded up to the next utia.
+	if accuracy == big.Below {
+		fee.Add(fee, big.NewInt(1))
+	}
+	return fee
+}
+
+func (c *celestiaDA) get(ctx context.Context, receipt *BlobReceipt) (*blob.Blob, error) {
+	if receipt.Layer != Celestia {
+		return nil, ErrLayerMismatch
+	}
+	namespace, err := share.NamespaceFromBytes(receipt.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.NewClient(ctx, c.url, c.token)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	return cli.Blob.Get(ctx, receipt.Height, namespace, blob.Commitment(receipt.Commitment))
+}
+
+// CelestiaVerifier checks that blobs are included in Celestia. The proofs it
+// returns can be referenced by fraud proofs.
+type CelestiaVerifier struct {
+	url   string
+	token string
+}
+
+func NewCelestiaVerifier(url string, token string) *CelestiaVerifier {
+	return &CelestiaVerifier{
+		url:   url,
+		token: token,
+	}
+}
+
+// Proof returns the inclusion proof of the blob with [commitment] posted
+// under [namespace] at [height], once the node checked it.
+func (v *CelestiaVerifier) Proof(
+	ctx context.Context,
+	height uint64,
+	namespace []byte,
+	commitment []byte,
+) (*blob.Proof, error) {
+	ns, err := share.NamespaceFromBytes(namespace)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := client.NewClient(ctx, v.url, v.token)
+	if err != nil {
+		return nil, err
+	}
+	defer cli.Close()
+
+	proof, err := actions.GetCelestiaProof(ctx, cli, height, ns, blob.Commitment(commitment))
+	if errors.Is(err, actions.ErrCelestiaBlobNotIncluded) {
+		return nil, ErrNotIncluded
+	}
+	return proof, err
+}
+
+// VerifyRecord checks the inclusion of a blob registered on-chain with
+// RegisterBlobCommitment and returns its proof.
+func (v *CelestiaVerifier) VerifyRecord(ctx context.Context, record *storage.BlobRecord) (*blob.Proof, error) {
+	if record.Layer != uint8(Celestia) {
+		return nil, ErrLayerMismatch
+	}
+	return v.Proof(ctx, record.Height, record.Namespace, record.Commitment)
+}
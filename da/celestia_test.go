@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+)
+
+func TestCelestiaVerifierLayerMismatch(t *testing.T) {
+	verifier := NewCelestiaVerifier("", "")
+	_, err := verifier.VerifyRecord(context.Background(), &storage.BlobRecord{
+		Layer:      uint8(Ethereum),
+		Commitment: []byte{0x01},
+	})
+	require.ErrorIs(t, err, ErrLayerMismatch)
+}
+
+func TestCelestiaSubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewCelestia(t)
+	backend := NewCelestia(fake.URL, "token", []byte{0x01, 0x02}, 0)
+
+	data := []byte("rollup batch")
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(fake.Height(), receipt.Height)
+
+	status, err := backend.GetStatus(ctx, receipt)
+	require.NoError(err)
+	require.Equal(StatusFinalized, status)
+
+	retrieved, err := backend.Retrieve(ctx, receipt)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+	require.NoError(backend.Verify(ctx, receipt))
+
+	verifier := NewCelestiaVerifier(fake.URL, "token")
+	_, err = verifier.VerifyRecord(ctx, &storage.BlobRecord{
+		Layer:      uint8(Celestia),
+		Height:     receipt.Height,
+		Namespace:  receipt.Namespace,
+		Commitment: []byte("unknown commitment"),
+	})
+	require.ErrorContains(err, datest.ErrCelestiaBlobNotFound.Error())
+}
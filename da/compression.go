@@ -0,0 +1,216 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/compression"
+)
+
+const (
+	// compressionMagic starts every payload posted by [NewCompressed].
+	compressionMagic      = 0xDA
+	compressionHeaderSize = 1 + 1 + 4
+
+	// CompressionOverhead is the most bytes [Compress] adds to a payload,
+	// which happens when it does not compress.
+	CompressionOverhead = compressionHeaderSize
+
+	// MaxDecompressedSize bounds the size a payload may claim in its
+	// compression header.
+	MaxDecompressedSize = 64 << 20
+)
+
+// Compression is the algorithm a payload is compressed with before being
+// posted. Its value is the codec byte of the compression header.
+type Compression uint8
+
+const (
+	CompressionNone Compression = iota
+	CompressionZstd
+	CompressionBrotli
+)
+
+var compressionNames = []string{"none", "zstd", "brotli"}
+
+func (c Compression) String() string {
+	if int(c) < len(compressionNames) {
+		return compressionNames[c]
+	}
+	return fmt.Sprintf("compression(%d)", uint8(c))
+}
+
+func (c Compression) MarshalText() ([]byte, error) {
+	if int(c) >= len(compressionNames) {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCompression, c)
+	}
+	return []byte(c.String()), nil
+}
+
+func (c *Compression) UnmarshalText(text []byte) error {
+	for i, name := range compressionNames {
+		if name == string(text) {
+			*c = Compression(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownCompression, text)
+}
+
+// Compress encodes [data] with [algorithm] behind a header naming the
+// algorithm and the size of [data]. If compressing does not make [data]
+// smaller, it is stored as is with [CompressionNone].
+func Compress(algorithm Compression, data []byte) ([]byte, Compression, error) {
+	if len(data) > MaxDecompressedSize {
+		return nil, 0, fmt.Errorf("%w: %d bytes", ErrPayloadTooLarge, len(data))
+	}
+	body := data
+	switch algorithm {
+	case CompressionNone:
+	case CompressionZstd:
+		compressor, err := compression.NewZstdCompressor(MaxDecompressedSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		body, err = compressor.Compress(data)
+		if err != nil {
+			return nil, 0, err
+		}
+	case CompressionBrotli:
+		var buf bytes.Buffer
+		w := brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+		if _, err := w.Write(data); err != nil {
+			return nil, 0, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, 0, err
+		}
+		body = buf.Bytes()
+	default:
+		return nil, 0, fmt.Errorf("%w: %d", ErrUnknownCompression, algorithm)
+	}
+	if len(body) >= len(data) {
+		algorithm, body = CompressionNone, data
+	}
+
+	encoded := make([]byte, compressionHeaderSize, compressionHeaderSize+len(body))
+	encoded[0] = compressionMagic
+	encoded[1] = byte(algorithm)
+	binary.BigEndian.PutUint32(encoded[2:], uint32(len(data)))
+	return append(encoded, body...), algorithm, nil
+}
+
+// Decompress returns the payload [Compress] encoded in [encoded].
+func Decompress(encoded []byte) ([]byte, error) {
+	if len(encoded) < compressionHeaderSize || encoded[0] != compressionMagic {
+		return nil, ErrInvalidCompressionHeader
+	}
+	algorithm := Compression(encoded[1])
+	size := binary.BigEndian.Uint32(encoded[2:])
+	if size > MaxDecompressedSize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrPayloadTooLarge, size)
+	}
+	body := encoded[compressionHeaderSize:]
+
+	var data []byte
+	switch algorithm {
+	case CompressionNone:
+		data = body
+	case CompressionZstd:
+		compressor, err := compression.NewZstdCompressor(int64(size))
+		if err != nil {
+			return nil, err
+		}
+		data, err = compressor.Decompress(body)
+		if err != nil {
+			return nil, err
+		}
+	case CompressionBrotli:
+		// Read one byte past [size] to catch a header claiming less than
+		// the body holds.
+		var err error
+		data, err = io.ReadAll(io.LimitReader(brotli.NewReader(bytes.NewReader(body)), int64(size)+1))
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownCompression, algorithm)
+	}
+	if uint32(len(data)) != size {
+		return nil, ErrInvalidCompressionHeader
+	}
+	return data, nil
+}
+
+// Encoding describes how the pipeline stages wrapping a backend transformed
+// a payload before it was posted.
+type Encoding struct {
+	// Digest is the sha256 of the payload before any stage, and Size its
+	// size.
+	Digest      ids.ID      `json:"digest"`
+	Size        uint64      `json:"size"`
+	Compression Compression `json:"compression"`
+	// Encryption is the scope the payload was encrypted for by
+	// [NewEncrypted], if it was.
+	Encryption *KeyScope `json:"encryption,omitempty"`
+}
+
+// setPayload records the payload a stage was given in [receipt]. The
+// outermost stage records last, so [receipt] ends up describing the payload
+// before any stage.
+func setPayload(receipt *BlobReceipt, data []byte) *Encoding {
+	if receipt.Encoding == nil {
+		receipt.Encoding = &Encoding{}
+	}
+	receipt.Encoding.Digest = sha256.Sum256(data)
+	receipt.Encoding.Size = uint64(len(data))
+	return receipt.Encoding
+}
+
+var _ DataAvailability = (*compressedDA)(nil)
+
+type compressedDA struct {
+	DataAvailability
+
+	algorithm Compression
+}
+
+// NewCompressed returns a backend compressing payloads with [algorithm]
+// before posting them to [backend], and decompressing them on retrieval.
+// Blobs posted through it must be retrieved through it.
+func NewCompressed(backend DataAvailability, algorithm Compression) DataAvailability {
+	return &compressedDA{
+		DataAvailability: backend,
+		algorithm:        algorithm,
+	}
+}
+
+func (c *compressedDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	encoded, algorithm, err := Compress(c.algorithm, data)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := c.DataAvailability.Submit(ctx, encoded)
+	if err != nil {
+		return nil, err
+	}
+	setPayload(receipt, data).Compression = algorithm
+	return receipt, nil
+}
+
+func (c *compressedDA) Retrieve(ctx context.Context, receipt *BlobReceipt) ([]byte, error) {
+	encoded, err := c.DataAvailability.Retrieve(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return Decompress(encoded)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+)
+
+func TestCompressionRoundTrip(t *testing.T) {
+	random := make([]byte, 1000)
+	_, err := rand.Read(random)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		algorithm Compression
+		data      []byte
+		used      Compression
+	}{
+		{name: "none", algorithm: CompressionNone, data: []byte("rollup batch"), used: CompressionNone},
+		{name: "zstd", algorithm: CompressionZstd, data: bytes.Repeat([]byte("rollup batch "), 1000), used: CompressionZstd},
+		{name: "brotli", algorithm: CompressionBrotli, data: bytes.Repeat([]byte("rollup batch "), 1000), used: CompressionBrotli},
+		{name: "incompressible", algorithm: CompressionZstd, data: random, used: CompressionNone},
+		{name: "incompressible brotli", algorithm: CompressionBrotli, data: random, used: CompressionNone},
+		{name: "empty", algorithm: CompressionZstd, data: []byte{}, used: CompressionNone},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require := require.New(t)
+
+			encoded, used, err := Compress(tt.algorithm, tt.data)
+			require.NoError(err)
+			require.Equal(tt.used, used)
+			if used != CompressionNone {
+				require.Less(len(encoded), len(tt.data))
+			}
+
+			decoded, err := Decompress(encoded)
+			require.NoError(err)
+			require.Equal(tt.data, decoded)
+		})
+	}
+}
+
+func TestDecompressInvalid(t *testing.T) {
+	require := require.New(t)
+
+	encoded, _, err := Compress(CompressionZstd, bytes.Repeat([]byte{0x01}, 1000))
+	require.NoError(err)
+
+	_, err = Decompress([]byte("raw payload"))
+	require.ErrorIs(err, ErrInvalidCompressionHeader)
+
+	// A header claiming less or more than the compressed payload holds.
+	for _, algorithm := range []Compression{CompressionZstd, CompressionBrotli} {
+		encoded, _, err := Compress(algorithm, bytes.Repeat([]byte{0x01}, 1000))
+		require.NoError(err)
+		for _, delta := range []int{-1, 1} {
+			lying := bytes.Clone(encoded)
+			lying[5] = byte(int(lying[5]) + delta)
+			_, err = Decompress(lying)
+			require.Error(err)
+		}
+	}
+
+	unknown := bytes.Clone(encoded)
+	unknown[1] = 0xff
+	_, err = Decompress(unknown)
+	require.ErrorIs(err, ErrUnknownCompression)
+}
+
+func TestCompressionText(t *testing.T) {
+	require := require.New(t)
+
+	var compression Compression
+	require.NoError(json.Unmarshal([]byte(`"zstd"`), &compression))
+	require.Equal(CompressionZstd, compression)
+	require.NoError(compression.UnmarshalText([]byte("brotli")))
+	require.Equal(CompressionBrotli, compression)
+	require.ErrorIs(compression.UnmarshalText([]byte("lz4")), ErrUnknownCompression)
+}
+
+func TestCompressedSubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewCelestia(t)
+	backend := NewCompressed(NewCelestia(fake.URL, "token", nil, 0), CompressionZstd)
+
+	data := bytes.Repeat([]byte("rollup batch "), 1000)
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Less(receipt.Size, uint64(len(data)))
+	require.Equal(&Encoding{
+		Digest:      ids.ID(sha256.Sum256(data)),
+		Size:        uint64(len(data)),
+		Compression: CompressionZstd,
+	}, receipt.Encoding)
+	require.NoError(backend.Verify(ctx, receipt))
+
+	retrieved, err := backend.Retrieve(ctx, receipt)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+}
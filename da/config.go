@@ -0,0 +1,177 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+type EthereumConfig struct {
+	RPC        string                `json:"rpc"`
+	PrivateKey string                `json:"private_key"`
+	Fees       actions.BlobFeeConfig `json:"fees"`
+	// Confirmations is the number of blocks built on top of a blob
+	// transaction before it is considered submitted.
+	Confirmations uint64 `json:"confirmations"`
+}
+
+type CelestiaConfig struct {
+	URL   string `json:"url"`
+	Token string `json:"token"`
+	// Namespace is a namespace ID or a full version 0 namespace.
+	Namespace codec.Bytes `json:"namespace,omitempty"`
+	// GasPrice is in utia per gas unit, 0 uses the minimum gas price of the
+	// node.
+	GasPrice float64 `json:"gas_price"`
+}
+
+type AvailConfig struct {
+	URL  string `json:"url"`
+	Seed string `json:"seed"`
+	// AppID is ignored if AppKey is set, the key is then registered if it
+	// does not exist yet.
+	AppID  uint32 `json:"app_id"`
+	AppKey string `json:"app_key,omitempty"`
+	// WaitForFinalization makes Submit wait for the block to be finalized
+	// instead of only included.
+	WaitForFinalization bool `json:"wait_for_finalization"`
+}
+
+type EigenDAConfig struct {
+	AuthKey   string                         `json:"auth_key"`
+	Disperser actions.EigenDADisperserConfig `json:"disperser"`
+	Retriever actions.EigenDARetrieverConfig `json:"retriever"`
+	Pricing   actions.EigenDAPricing         `json:"pricing"`
+}
+
+// Config selects a DA backend by [Layer] and holds the settings of every
+// backend.
+type Config struct {
+	Layer    Layer          `json:"layer"`
+	Ethereum EthereumConfig `json:"ethereum"`
+	Celestia CelestiaConfig `json:"celestia"`
+	Avail    AvailConfig    `json:"avail"`
+	EigenDA  EigenDAConfig  `json:"eigenda"`
+
+	// Redundancy lists the layers [NewRedundant] posts to, with their
+	// settings taken from the fields above.
+	Redundancy RedundancyPolicy `json:"redundancy"`
+	// Failover lists the layers [NewWithFailover] routes to.
+	Failover FailoverPolicy `json:"failover"`
+	// Costs lists the layers [NewCostOracleFromConfig] quotes.
+	Costs CostOracleConfig `json:"costs"`
+	// Sharding lists the layers [NewSharded] spreads shards over.
+	Sharding ShardingPolicy `json:"sharding"`
+}
+
+func NewDefaultConfig() Config {
+	return Config{
+		Layer: Celestia,
+		Ethereum: EthereumConfig{
+			Fees:          actions.NewDefaultBlobFeeConfig(),
+			Confirmations: 2,
+		},
+		Avail: AvailConfig{
+			AppID: 1,
+		},
+		EigenDA: EigenDAConfig{
+			Disperser: actions.NewDefaultEigenDADisperserConfig(),
+			Retriever: actions.NewDefaultEigenDARetrieverConfig(),
+			Pricing:   actions.NewDefaultEigenDAPricing(),
+		},
+		Redundancy: NewDefaultRedundancyPolicy(),
+		Failover:   NewDefaultFailoverPolicy(),
+		Sharding:   NewDefaultShardingPolicy(),
+	}
+}
+
+// New returns the backend selected by [config.Layer].
+func New(ctx context.Context, config Config) (DataAvailability, error) {
+	switch config.Layer {
+	case Ethereum:
+		client, err := ethclient.DialContext(ctx, config.Ethereum.RPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ethereum client: %w", err)
+		}
+		return NewEthereum(client, config.Ethereum.PrivateKey, config.Ethereum.Fees, config.Ethereum.Confirmations), nil
+	case Celestia:
+		return NewCelestia(config.Celestia.URL, config.Celestia.Token, config.Celestia.Namespace, config.Celestia.GasPrice), nil
+	case Avail:
+		return NewAvail(config.Avail)
+	case EigenDA:
+		return NewEigenDA(config.EigenDA)
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownLayer, config.Layer)
+	}
+}
+
+// NewRedundant returns a dispatcher posting to every layer of
+// [config.Redundancy], each configured like [New] would.
+func NewRedundant(ctx context.Context, config Config) (*Dispatcher, error) {
+	backends, err := newBackends(ctx, config, config.Redundancy.Layers)
+	if err != nil {
+		return nil, err
+	}
+	return NewDispatcher(backends, config.Redundancy)
+}
+
+// NewWithFailover returns a failover routing to the layers of
+// [config.Failover], each configured like [New] would.
+func NewWithFailover(ctx context.Context, config Config) (*Failover, error) {
+	layers := make([]Layer, len(config.Failover.Layers))
+	for i, layer := range config.Failover.Layers {
+		layers[i] = layer.Layer
+	}
+	backends, err := newBackends(ctx, config, layers)
+	if err != nil {
+		return nil, err
+	}
+	return NewFailover(backends, config.Failover)
+}
+
+// NewCostOracleFromConfig returns an oracle quoting the layers of
+// [config.Costs] with [NewQuoter], so no credentials are needed.
+func NewCostOracleFromConfig(ctx context.Context, config Config) (*CostOracle, error) {
+	quoters := make([]Quoter, 0, len(config.Costs.Layers))
+	for _, layer := range config.Costs.Layers {
+		layerConfig := config
+		layerConfig.Layer = layer
+		quoter, err := NewQuoter(ctx, layerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s quoter: %w", layer, err)
+		}
+		quoters = append(quoters, quoter)
+	}
+	return NewCostOracle(quoters, config.Costs.USDPrices), nil
+}
+
+// NewSharded returns a sharder spreading shards over the layers of
+// [config.Sharding], each configured like [New] would.
+func NewSharded(ctx context.Context, config Config) (*Sharder, error) {
+	backends, err := newBackends(ctx, config, config.Sharding.Layers)
+	if err != nil {
+		return nil, err
+	}
+	return NewSharder(backends, config.Sharding)
+}
+
+func newBackends(ctx context.Context, config Config, layers []Layer) ([]DataAvailability, error) {
+	backends := make([]DataAvailability, 0, len(layers))
+	for _, layer := range layers {
+		layerConfig := config
+		layerConfig.Layer = layer
+		backend, err := New(ctx, layerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s backend: %w", layer, err)
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"math/big"
+	"sync"
+)
+
+// CostEstimator is implemented by backends that can price a submission
+// before posting it.
+type CostEstimator interface {
+	// EstimateCost returns the fee of posting [size] bytes, in the smallest
+	// unit of the token of the layer.
+	EstimateCost(ctx context.Context, size uint64) (*big.Int, error)
+}
+
+// Token is the currency fees of a layer are paid in.
+type Token struct {
+	Symbol   string `json:"symbol"`
+	Decimals uint8  `json:"decimals"`
+}
+
+var layerTokens = map[Layer]Token{
+	Ethereum: {Symbol: "ETH", Decimals: 18},
+	Celestia: {Symbol: "TIA", Decimals: 6},
+	Avail:    {Symbol: "AVAIL", Decimals: 18},
+	EigenDA:  {Symbol: "ETH", Decimals: 18},
+}
+
+// CostQuote is the estimated cost of posting a payload to a layer.
+type CostQuote struct {
+	Layer Layer  `json:"layer"`
+	Size  uint64 `json:"size"`
+	Token Token  `json:"token"`
+	// Fee is in the smallest unit of [Token], as a decimal string.
+	Fee string `json:"fee,omitempty"`
+	// Cost is [Fee] in whole tokens.
+	Cost float64 `json:"cost,omitempty"`
+	// USD is [Cost] in US dollars, set if the price of [Token] is known.
+	USD float64 `json:"usd,omitempty"`
+	// Error is set if the layer could not be priced.
+	Error string `json:"error,omitempty"`
+}
+
+// CostOracleConfig selects the layers a [CostOracle] quotes.
+type CostOracleConfig struct {
+	Layers []Layer `json:"layers"`
+	// USDPrices are the prices of the token of each layer in US dollars.
+	// Layers without a price are quoted in their token only.
+	USDPrices map[Layer]float64 `json:"usd_prices,omitempty"`
+}
+
+// CostOracle quotes the cost of posting a payload to a set of layers.
+type CostOracle struct {
+	quoters   []Quoter
+	usdPrices map[Layer]float64
+}
+
+// NewCostOracle returns an oracle quoting [quoters], converting fees to US
+// dollars with [usdPrices].
+func NewCostOracle(quoters []Quoter, usdPrices map[Layer]float64) *CostOracle {
+	return &CostOracle{
+		quoters:   quoters,
+		usdPrices: usdPrices,
+	}
+}
+
+// Quote prices a payload of [size] bytes on every layer in parallel. The
+// quotes are in the order of the quoters of the oracle; a layer that could
+// not be priced has its [CostQuote.Error] set.
+func (o *CostOracle) Quote(ctx context.Context, size uint64) []*CostQuote {
+	quotes := make([]*CostQuote, len(o.quoters))
+	var wg sync.WaitGroup
+	for i, quoter := range o.quoters {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			quotes[i] = o.quote(ctx, quoter, size)
+		}()
+	}
+	wg.Wait()
+	return quotes
+}
+
+func (o *CostOracle) quote(ctx context.Context, quoter Quoter, size uint64) *CostQuote {
+	quote := &CostQuote{
+		Layer: quoter.Layer(),
+		Size:  size,
+		Token: layerTokens[quoter.Layer()],
+	}
+	estimator, ok := quoter.(CostEstimator)
+	if !ok {
+		quote.Error = ErrCostNotSupported.Error()
+		return quote
+	}
+	fee, err := estimator.EstimateCost(ctx, size)
+	if err != nil {
+		quote.Error = err.Error()
+		return quote
+	}
+	quote.Fee = fee.String()
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(quote.Token.Decimals)), nil))
+	quote.Cost, _ = new(big.Float).Quo(new(big.Float).SetInt(fee), scale).Float64()
+	if price, ok := o.usdPrices[quote.Layer]; ok {
+		quote.USD = quote.Cost * price
+	}
+	return quote
+}
+
+// Cheapest returns the quote with the lowest cost in US dollars among
+// [quotes] that have one.
+func Cheapest(quotes []*CostQuote) (*CostQuote, bool) {
+	var cheapest *CostQuote
+	for _, quote := range quotes {
+		if quote.Error != "" || quote.USD == 0 {
+			continue
+		}
+		if cheapest == nil || quote.USD < cheapest.USD {
+			cheapest = quote
+		}
+	}
+	return cheapest, cheapest != nil
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+)
+
+// pricedBackend is a [memoryBackend] charging [fee] per byte.
+type pricedBackend struct {
+	*memoryBackend
+
+	fee int64
+}
+
+func (p *pricedBackend) EstimateCost(_ context.Context, size uint64) (*big.Int, error) {
+	return big.NewInt(p.fee * int64(size)), nil
+}
+
+func TestCostOracle(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	node := datest.NewEthereum(t, 20*time.Millisecond)
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(err)
+	t.Cleanup(client.Close)
+
+	oracle := NewCostOracle(
+		[]Quoter{
+			NewEthereum(client, node.PrivateKey, actions.NewDefaultBlobFeeConfig(), 1),
+			NewCelestia("", "", nil, 0),
+			&pricedBackend{memoryBackend: &memoryBackend{layer: EigenDA}, fee: 1e12},
+			&memoryBackend{layer: Avail},
+		},
+		map[Layer]float64{Ethereum: 2000, Celestia: 5, EigenDA: 2000},
+	)
+
+	quotes := oracle.Quote(ctx, 1000)
+	require.Len(quotes, 4)
+
+	// One blob at the minimum blob base fee, and the gas of one transaction
+	// at the base fee and tip of the fake.
+	ethereum := quotes[0]
+	require.Equal(Ethereum, ethereum.Layer)
+	require.Equal("ETH", ethereum.Token.Symbol)
+	fee := params.BlobTxBlobGasPerBlob + params.TxGas*2*params.GWei
+	require.Equal(big.NewInt(int64(fee)).String(), ethereum.Fee)
+	require.InDelta(float64(fee)/params.Ether, ethereum.Cost, 1e-12)
+	require.InDelta(ethereum.Cost*2000, ethereum.USD, 1e-9)
+
+	celestia := quotes[1]
+	require.Equal(Celestia, celestia.Layer)
+	gas := actions.EstimateCelestiaGas(1000)
+	require.InDelta(float64(gas)*appconsts.DefaultMinGasPrice/1e6, celestia.Cost, 1e-6)
+	require.InDelta(celestia.Cost*5, celestia.USD, 1e-9)
+
+	eigenDA := quotes[2]
+	require.Equal("1000000000000000", eigenDA.Fee)
+	require.InDelta(2, eigenDA.USD, 1e-9)
+
+	require.Equal(ErrCostNotSupported.Error(), quotes[3].Error)
+	require.Empty(quotes[3].Fee)
+
+	cheapest, ok := Cheapest(quotes)
+	require.True(ok)
+	require.Equal(Celestia, cheapest.Layer)
+	_, ok = Cheapest(quotes[3:])
+	require.False(ok)
+}
+
+func TestCostOracleFromConfig(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	node := datest.NewEthereum(t, 20*time.Millisecond)
+	config := NewDefaultConfig()
+	// No private key, token, seed or auth key is configured.
+	config.Ethereum.RPC = node.URL
+	config.Costs.Layers = []Layer{Ethereum, Celestia, EigenDA}
+	oracle, err := NewCostOracleFromConfig(ctx, config)
+	require.NoError(err)
+
+	quotes := oracle.Quote(ctx, 1000)
+	require.Len(quotes, 3)
+	for _, quote := range quotes {
+		require.Empty(quote.Error)
+	}
+	fee := params.BlobTxBlobGasPerBlob + params.TxGas*2*params.GWei
+	require.Equal(big.NewInt(int64(fee)).String(), quotes[0].Fee)
+	celestia, err := NewCelestia("", "", nil, 0).(CostEstimator).EstimateCost(ctx, 1000)
+	require.NoError(err)
+	require.Equal(celestia.String(), quotes[1].Fee)
+	require.Equal(config.EigenDA.Pricing.Fee(1000).String(), quotes[2].Fee)
+}
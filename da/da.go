@@ -0,0 +1,167 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package da puts the data availability adapters in [actions] behind a single
+// interface so a backend can be selected by configuration.
+package da
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// DataAvailability is implemented by every DA backend.
+type DataAvailability interface {
+	// Layer returns the DA layer the backend posts to.
+	Layer() Layer
+
+	// Submit posts [data] to the DA layer and returns a receipt that can
+	// later be used to query, retrieve and verify it.
+	Submit(ctx context.Context, data []byte) (*BlobReceipt, error)
+
+	// GetStatus returns the current status of a previously submitted blob.
+	GetStatus(ctx context.Context, receipt *BlobReceipt) (Status, error)
+
+	// Retrieve reads the blob referenced by [receipt] back from the DA layer.
+	Retrieve(ctx context.Context, receipt *BlobReceipt) ([]byte, error)
+
+	// Verify checks that the blob referenced by [receipt] is available on
+	// the DA layer.
+	Verify(ctx context.Context, receipt *BlobReceipt) error
+}
+
+type Layer uint8
+
+const (
+	Ethereum = Layer(consts.EthereumLayerID)
+	Celestia = Layer(consts.CelestiaLayerID)
+	Avail    = Layer(consts.AvailLayerID)
+	EigenDA  = Layer(consts.EigenDALayerID)
+)
+
+var layerNames = map[Layer]string{
+	Ethereum: "ethereum",
+	Celestia: "celestia",
+	Avail:    "avail",
+	EigenDA:  "eigenda",
+}
+
+func (l Layer) String() string {
+	if name, ok := layerNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("layer(%d)", uint8(l))
+}
+
+func (l Layer) MarshalText() ([]byte, error) {
+	if _, ok := layerNames[l]; !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownLayer, l)
+	}
+	return []byte(l.String()), nil
+}
+
+func (l *Layer) UnmarshalText(text []byte) error {
+	name := strings.ToLower(string(text))
+	for layer, layerName := range layerNames {
+		if layerName == name {
+			*l = layer
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownLayer, text)
+}
+
+type Status uint8
+
+const (
+	StatusUnknown Status = iota
+	StatusProcessing
+	StatusConfirmed
+	StatusFinalized
+	StatusFailed
+)
+
+var statusNames = []string{"unknown", "processing", "confirmed", "finalized", "failed"}
+
+func (s Status) String() string {
+	if int(s) < len(statusNames) {
+		return statusNames[s]
+	}
+	return fmt.Sprintf("status(%d)", uint8(s))
+}
+
+func (s Status) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Status) UnmarshalText(text []byte) error {
+	for i, name := range statusNames {
+		if name == string(text) {
+			*s = Status(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown status %q", text)
+}
+
+// BlobReceipt describes where a blob lives on a DA layer. Only the locator
+// fields relevant to [Layer] are populated.
+type BlobReceipt struct {
+	Layer  Layer  `json:"layer"`
+	Status Status `json:"status"`
+
+	// Digest is the sha256 of the submitted payload.
+	Digest ids.ID `json:"digest"`
+	Size   uint64 `json:"size"`
+
+	Height uint64 `json:"height,omitempty"`
+	// Index is the position of the transaction in its block, if the layer
+	// needs it to locate the data.
+	Index      uint32      `json:"index,omitempty"`
+	TxHash     codec.Bytes `json:"tx_hash,omitempty"`
+	BlockHash  codec.Bytes `json:"block_hash,omitempty"`
+	Namespace  codec.Bytes `json:"namespace,omitempty"`
+	Commitment codec.Bytes `json:"commitment,omitempty"`
+
+	// ExtraTxHashes holds the transactions following [TxHash] when the
+	// payload did not fit in a single one.
+	ExtraTxHashes []codec.Bytes `json:"extra_tx_hashes,omitempty"`
+	// BlobHashes are the KZG versioned hashes of the EIP-4844 blobs carrying
+	// the payload, in order.
+	BlobHashes []codec.Bytes `json:"blob_hashes,omitempty"`
+
+	// RequestID is the ID of the EigenDA dispersal request.
+	RequestID codec.Bytes `json:"request_id,omitempty"`
+	// Certificate is the certificate EigenDA issued for the blob.
+	Certificate *actions.EigenDACertificate `json:"certificate,omitempty"`
+
+	// Encoding is set if the payload went through pipeline stages, such as
+	// [NewCompressed], before being posted. [Digest] and [Size] then
+	// describe the posted bytes.
+	Encoding *Encoding `json:"encoding,omitempty"`
+}
+
+func newReceipt(layer Layer, data []byte) *BlobReceipt {
+	return &BlobReceipt{
+		Layer:  layer,
+		Status: StatusProcessing,
+		Digest: sha256.Sum256(data),
+		Size:   uint64(len(data)),
+	}
+}
+
+// checkDigest ensures [data] matches the payload [receipt] was issued for.
+func checkDigest(receipt *BlobReceipt, data []byte) error {
+	if ids.ID(sha256.Sum256(data)) != receipt.Digest {
+		return ErrDigestMismatch
+	}
+	return nil
+}
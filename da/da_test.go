@@ -0,0 +1,54 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayerText(t *testing.T) {
+	for _, layer := range []Layer{Ethereum, Celestia, Avail, EigenDA} {
+		t.Run(layer.String(), func(t *testing.T) {
+			require := require.New(t)
+
+			text, err := layer.MarshalText()
+			require.NoError(err)
+
+			var parsed Layer
+			require.NoError(parsed.UnmarshalText(text))
+			require.Equal(layer, parsed)
+		})
+	}
+
+	var layer Layer
+	require.ErrorIs(t, layer.UnmarshalText([]byte("arweave")), ErrUnknownLayer)
+	_, err := Layer(0).MarshalText()
+	require.ErrorIs(t, err, ErrUnknownLayer)
+}
+
+func TestConfigJSON(t *testing.T) {
+	require := require.New(t)
+
+	var config Config
+	require.NoError(json.Unmarshal([]byte(`{"layer":"eigenda","eigenda":{"auth_key":"key"},"redundancy":{"layers":["celestia","eigenda"],"quorum":1},"sharding":{"layers":["celestia","avail"],"parity_ratio":1}}`), &config))
+	require.Equal(EigenDA, config.Layer)
+	require.Equal("key", config.EigenDA.AuthKey)
+	require.Equal([]Layer{Celestia, EigenDA}, config.Redundancy.Layers)
+	require.Equal(1, config.Redundancy.Quorum)
+	require.Equal([]Layer{Celestia, Avail}, config.Sharding.Layers)
+	require.InDelta(1, config.Sharding.ParityRatio, 0)
+}
+
+func TestReceiptDigest(t *testing.T) {
+	require := require.New(t)
+
+	data := []byte("rollup batch")
+	receipt := newReceipt(Celestia, data)
+	require.Equal(uint64(len(data)), receipt.Size)
+	require.NoError(checkDigest(receipt, data))
+	require.ErrorIs(checkDigest(receipt, []byte("other batch")), ErrDigestMismatch)
+}
@@ -0,0 +1,922 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package datest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/gorilla/websocket"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+const (
+	// AvailDataAvailabilityPallet and AvailSubmitDataCall index the
+	// submit_data call in the Avail runtime.
+	AvailDataAvailabilityPallet = 29
+	AvailSubmitDataCall         = 1
+	// AvailFinalityDepth is how far below the head the finalized block is.
+	AvailFinalityDepth = 1
+	// AvailLengthFeePerByte is the length fee of extrinsics, in plancks.
+	AvailLengthFeePerByte = 1_000_000_000
+)
+
+var (
+	ErrAvailBlockNotFound     = errors.New("block not found")
+	ErrAvailExtrinsicNotFound = errors.New("extrinsic not found")
+	ErrAvailNotDataExtrinsic  = errors.New("extrinsic does not submit data")
+	ErrAvailUnknownRuntimeAPI = errors.New("unknown runtime API")
+	ErrAvailInvalidExtrinsic  = errors.New("invalid extrinsic")
+	ErrAvailUnknownCall       = errors.New("unknown call")
+	ErrAvailStaleNonce        = errors.New("stale nonce")
+	ErrAvailAppKeyExists      = errors.New("app key already exists")
+)
+
+// AvailSubmission is a data extrinsic included by [Avail].
+type AvailSubmission struct {
+	BlockHash   common.Hash
+	BlockNumber uint32
+	Index       uint32
+	AppID       uint32
+}
+
+// availCall is a decoded signed extrinsic.
+type availCall struct {
+	signer [32]byte
+	nonce  uint64
+	appID  uint32
+	pallet byte
+	method byte
+	args   []byte
+}
+
+// bytesArg returns the argument of calls taking a single byte vector.
+func (c *availCall) bytesArg() ([]byte, bool) {
+	size, n := readCompact(c.args)
+	if n == 0 || uint64(len(c.args)-n) != size {
+		return nil, false
+	}
+	return c.args[n:], true
+}
+
+type availExtrinsic struct {
+	encoded []byte
+	// call is nil if the extrinsic could not be decoded.
+	call *availCall
+	// leaf is the index of the data in the blob root of the block, or -1
+	// if the extrinsic does not submit data.
+	leaf int
+	// events are the encoded event records deposited by the extrinsic.
+	events [][]byte
+}
+
+type availBlock struct {
+	header     availHeader
+	extrinsics []availExtrinsic
+	leaves     []common.Hash
+	blobRoot   common.Hash
+}
+
+type availHeader struct {
+	ParentHash     common.Hash     `json:"parentHash"`
+	Number         hexutil.Uint64  `json:"number"`
+	StateRoot      common.Hash     `json:"stateRoot"`
+	ExtrinsicsRoot common.Hash     `json:"extrinsicsRoot"`
+	Digest         json.RawMessage `json:"digest"`
+	Extension      struct {
+		V3 struct {
+			AppLookup struct {
+				Size  uint32            `json:"size"`
+				Index []json.RawMessage `json:"index"`
+			} `json:"appLookup"`
+			Commitment struct {
+				Rows       uint16        `json:"rows"`
+				Cols       uint16        `json:"cols"`
+				Commitment hexutil.Bytes `json:"commitment"`
+				DataRoot   common.Hash   `json:"dataRoot"`
+			} `json:"commitment"`
+		} `json:"V3"`
+	} `json:"extension"`
+}
+
+func (h *availHeader) hash() common.Hash {
+	b, _ := json.Marshal(h)
+	return crypto.Keccak256Hash(b)
+}
+
+// availAppKey is a registered application key.
+type availAppKey struct {
+	owner [32]byte
+	id    uint32
+	// number is the block the key was registered in.
+	number uint64
+}
+
+// availWatcher is an extrinsic watched with author_submitAndWatchExtrinsic
+// until its block is finalized.
+type availWatcher struct {
+	notifier *rpc.Notifier
+	id       rpc.ID
+	number   uint64
+}
+
+// Avail is a fake Avail node serving the substrate JSON-RPC methods used to
+// submit data, read it back and check it.
+//
+// It serves the metadata of a minimal runtime, see [availMetadata], the
+// System.Account, System.Events and DataAvailability storage, and accepts
+// submit_data and create_application_key extrinsics through
+// author_submitExtrinsic and author_submitAndWatchExtrinsic, so the SDK can
+// be used against it. Each submitted extrinsic is included in a new block
+// and watchers are told once that block is finalized, which happens when
+// [AvailFinalityDepth] blocks are built on top of it. Signatures are not
+// checked.
+//
+// Blocks are read with chain_getBlock, chain_getHeader, chain_getBlockHash
+// and chain_getFinalizedHead, and data is checked with kate_queryDataProof.
+// The length fee of extrinsics is served through state_call.
+type Avail struct {
+	// URL is the websocket JSON-RPC endpoint.
+	URL string
+	// Signer is the public key [Avail.Submit] submits data with.
+	Signer [32]byte
+
+	metadata        *types.Metadata
+	encodedMetadata []byte
+
+	lock      sync.Mutex
+	blocks    []*availBlock
+	byHash    map[common.Hash]*availBlock
+	appKeys   map[string]availAppKey
+	nextAppID uint32
+	watchers  []availWatcher
+}
+
+// NewAvail starts a fake Avail node. It is stopped when [t] completes.
+func NewAvail(t testing.TB) *Avail {
+	a := &Avail{
+		byHash:  make(map[common.Hash]*availBlock),
+		appKeys: make(map[string]availAppKey),
+		// App ID 0 is the default application.
+		nextAppID: 1,
+	}
+	if _, err := rand.Read(a.Signer[:]); err != nil {
+		t.Fatal(err)
+	}
+	var err error
+	a.metadata, a.encodedMetadata, err = availMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.appendBlock(nil)
+
+	server := rpc.NewServer()
+	for namespace, service := range map[string]any{
+		"author": &availAuthorAPI{a: a},
+		"chain":  &availChainAPI{a: a},
+		"kate":   &availKateAPI{a: a},
+		"state":  &availStateAPI{a: a},
+	} {
+		if err := server.RegisterName(namespace, service); err != nil {
+			t.Fatal(err)
+		}
+	}
+	httpServer := httptest.NewServer(serveSubstrate(server))
+	a.URL = "ws" + strings.TrimPrefix(httpServer.URL, "http")
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return a
+}
+
+// Submit includes a block holding one data extrinsic per item of [data],
+// submitted under [appID] by [Signer].
+func (a *Avail) Submit(appID uint32, data ...[]byte) []AvailSubmission {
+	extrinsics := make([][]byte, len(data))
+	for i, d := range data {
+		extrinsics[i] = EncodeAvailDataExtrinsic(a.Signer, appID, d)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	block := a.appendBlock(extrinsics)
+	submissions := make([]AvailSubmission, len(data))
+	for i := range data {
+		submissions[i] = AvailSubmission{
+			BlockHash:   block.header.hash(),
+			BlockNumber: uint32(block.header.Number),
+			Index:       uint32(i),
+			AppID:       appID,
+		}
+	}
+	return submissions
+}
+
+// BuildBlock includes an empty block and returns its hash. The block
+// finalizes the blocks [AvailFinalityDepth] below it.
+func (a *Avail) BuildBlock() common.Hash {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.appendBlock(nil).header.hash()
+}
+
+// AppID returns the ID registered for [appKey] by a create_application_key
+// extrinsic.
+func (a *Avail) AppID(appKey string) (uint32, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	key, ok := a.appKeys[appKey]
+	return key.id, ok
+}
+
+// include checks an extrinsic submitted to the node and includes it in a new
+// block.
+func (a *Avail) include(encoded []byte) (*availBlock, error) {
+	call, err := decodeAvailExtrinsic(encoded)
+	if err != nil {
+		return nil, err
+	}
+	head := a.blocks[len(a.blocks)-1]
+	if nonce := a.nonce(call.signer, uint64(head.header.Number)); call.nonce != nonce {
+		return nil, fmt.Errorf("%w: %d, expected %d", ErrAvailStaleNonce, call.nonce, nonce)
+	}
+	if call.pallet != AvailDataAvailabilityPallet {
+		return nil, fmt.Errorf("%w: %d.%d", ErrAvailUnknownCall, call.pallet, call.method)
+	}
+	arg, ok := call.bytesArg()
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed arguments", ErrAvailInvalidExtrinsic)
+	}
+	switch call.method {
+	case AvailSubmitDataCall:
+	case AvailCreateApplicationKeyCall:
+		if _, ok := a.appKeys[string(arg)]; ok {
+			return nil, fmt.Errorf("%w: %q", ErrAvailAppKeyExists, arg)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %d.%d", ErrAvailUnknownCall, call.pallet, call.method)
+	}
+	return a.appendBlock([][]byte{encoded}), nil
+}
+
+func (a *Avail) appendBlock(extrinsics [][]byte) *availBlock {
+	block := &availBlock{}
+	if len(a.blocks) > 0 {
+		parent := a.blocks[len(a.blocks)-1]
+		block.header.ParentHash = parent.header.hash()
+		block.header.Number = parent.header.Number + 1
+	}
+	block.header.Digest = json.RawMessage(`{"logs":[]}`)
+	block.header.Extension.V3.AppLookup.Index = []json.RawMessage{}
+	block.header.Extension.V3.Commitment.Rows = 1
+	block.header.Extension.V3.Commitment.Cols = 4
+
+	for i, encoded := range extrinsics {
+		ext := availExtrinsic{encoded: encoded, leaf: -1}
+		ext.call, _ = decodeAvailExtrinsic(encoded)
+		if data, ok := decodeAvailData(encoded); ok {
+			ext.leaf = len(block.leaves)
+			block.leaves = append(block.leaves, crypto.Keccak256Hash(data))
+		}
+		ext.events = a.apply(uint32(i), ext.call, uint64(block.header.Number))
+		block.extrinsics = append(block.extrinsics, ext)
+	}
+	block.blobRoot, _ = availMerkleProof(block.leaves, 0)
+	// The bridge root is always empty.
+	block.header.Extension.V3.Commitment.DataRoot = crypto.Keccak256Hash(block.blobRoot[:], common.Hash{}.Bytes())
+
+	a.blocks = append(a.blocks, block)
+	a.byHash[block.header.hash()] = block
+	a.notifyFinalized()
+	return block
+}
+
+// apply runs the extrinsic at [index] of the block [number] and returns the
+// events it deposits.
+func (a *Avail) apply(index uint32, call *availCall, number uint64) [][]byte {
+	if call == nil || call.pallet != AvailDataAvailabilityPallet {
+		return nil
+	}
+	arg, ok := call.bytesArg()
+	if !ok {
+		return nil
+	}
+	var event []byte
+	switch call.method {
+	case AvailSubmitDataCall:
+		event = []byte{AvailDataAvailabilityPallet, availDataSubmittedEvent}
+		event = append(event, call.signer[:]...)
+		event = append(event, crypto.Keccak256(arg)...)
+	case AvailCreateApplicationKeyCall:
+		if _, ok := a.appKeys[string(arg)]; ok {
+			return nil
+		}
+		key := availAppKey{owner: call.signer, id: a.nextAppID, number: number}
+		a.appKeys[string(arg)] = key
+		a.nextAppID++
+		event = []byte{AvailDataAvailabilityPallet, availApplicationKeyCreatedEvent}
+		event = append(appendCompact(event, uint64(len(arg))), arg...)
+		event = append(event, call.signer[:]...)
+		event = appendCompact(event, uint64(key.id))
+	default:
+		return nil
+	}
+	// The dispatch info of ExtrinsicSuccess: no weight, normal class, paid.
+	success := []byte{AvailSystemPallet, availExtrinsicSuccessEvent, 0x00, 0x00, 0x00, 0x00}
+	return [][]byte{encodeAvailEventRecord(index, event), encodeAvailEventRecord(index, success)}
+}
+
+// encodeAvailEventRecord encodes an event deposited while applying the
+// extrinsic at [index], without topics.
+func encodeAvailEventRecord(index uint32, event []byte) []byte {
+	record := []byte{0x00} // Phase::ApplyExtrinsic
+	record = binary.LittleEndian.AppendUint32(record, index)
+	record = append(record, event...)
+	return append(record, 0x00)
+}
+
+// notifyFinalized tells the watchers whose block is finalized.
+func (a *Avail) notifyFinalized() {
+	finalized := a.finalized()
+	watchers := a.watchers[:0]
+	for _, w := range a.watchers {
+		if w.number > finalized {
+			watchers = append(watchers, w)
+			continue
+		}
+		_ = w.notifier.Notify(w.id, map[string]common.Hash{
+			"finalized": a.blocks[w.number].header.hash(),
+		})
+	}
+	a.watchers = watchers
+}
+
+func (a *Avail) finalized() uint64 {
+	return uint64(max(len(a.blocks)-1-AvailFinalityDepth, 0))
+}
+
+// nonce returns the number of extrinsics [signer] got included up to the
+// block [number].
+func (a *Avail) nonce(signer [32]byte, number uint64) uint64 {
+	var nonce uint64
+	for _, block := range a.blocks[:number+1] {
+		for _, ext := range block.extrinsics {
+			if ext.call != nil && ext.call.signer == signer {
+				nonce++
+			}
+		}
+	}
+	return nonce
+}
+
+// storage returns the value stored under [key] at [block], or nil if there
+// is none.
+func (a *Avail) storage(key []byte, block *availBlock) []byte {
+	number := uint64(block.header.Number)
+	switch {
+	case bytes.Equal(key, a.storageKey("System", "Events")):
+		var records [][]byte
+		for _, ext := range block.extrinsics {
+			records = append(records, ext.events...)
+		}
+		return append(appendCompact(nil, uint64(len(records))), bytes.Join(records, nil)...)
+	case bytes.Equal(key, a.storageKey("DataAvailability", "NextAppId")):
+		next := uint32(1)
+		for _, appKey := range a.appKeys {
+			if appKey.number <= number {
+				next++
+			}
+		}
+		return appendCompact(nil, uint64(next))
+	}
+
+	// Map keys end with their Blake2_128Concat hashed argument.
+	const prefixSize = 16 + 16 + 16
+	if len(key) <= prefixSize {
+		return nil
+	}
+	arg := key[prefixSize:]
+	switch {
+	case bytes.Equal(key, a.storageKey("System", "Account", arg)):
+		var signer [32]byte
+		copy(signer[:], arg)
+		info := binary.LittleEndian.AppendUint32(nil, uint32(a.nonce(signer, number)))
+		// Reference counters and balances.
+		return append(info, make([]byte, 3*4+4*16)...)
+	case bytes.Equal(key, a.storageKey("DataAvailability", "AppKeys", arg)):
+		size, n := readCompact(arg)
+		if n == 0 || uint64(len(arg)-n) != size {
+			return nil
+		}
+		appKey, ok := a.appKeys[string(arg[n:])]
+		if !ok || appKey.number > number {
+			return nil
+		}
+		return appendCompact(appKey.owner[:], uint64(appKey.id))
+	}
+	return nil
+}
+
+func (a *Avail) storageKey(pallet string, item string, args ...[]byte) []byte {
+	key, err := types.CreateStorageKey(a.metadata, pallet, item, args...)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+func (a *Avail) block(hash *common.Hash) (*availBlock, error) {
+	if hash == nil {
+		return a.blocks[len(a.blocks)-1], nil
+	}
+	block, ok := a.byHash[*hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAvailBlockNotFound, hash)
+	}
+	return block, nil
+}
+
+// EncodeAvailDataExtrinsic encodes a signed submit_data extrinsic carrying
+// [data] under [appID]. The signature is left empty.
+func EncodeAvailDataExtrinsic(signer [32]byte, appID uint32, data []byte) []byte {
+	body := []byte{0x84, 0x00} // Signed, version 4. MultiAddress::Id
+	body = append(body, signer[:]...)
+	body = append(body, 0x01) // MultiSignature::Sr25519
+	body = append(body, make([]byte, 64)...)
+	body = append(body, 0x00)     // Immortal era
+	body = appendCompact(body, 0) // Nonce
+	body = appendCompact(body, 0) // Tip
+	body = appendCompact(body, uint64(appID))
+	body = append(body, AvailDataAvailabilityPallet, AvailSubmitDataCall)
+	body = appendCompact(body, uint64(len(data)))
+	body = append(body, data...)
+	return append(appendCompact(nil, uint64(len(body))), body...)
+}
+
+// decodeAvailExtrinsic decodes a signed extrinsic carrying the signed
+// extensions of the Avail runtime.
+func decodeAvailExtrinsic(encoded []byte) (*availCall, error) {
+	size, n := readCompact(encoded)
+	if n == 0 || uint64(len(encoded)-n) != size {
+		return nil, fmt.Errorf("%w: length prefix", ErrAvailInvalidExtrinsic)
+	}
+	body := encoded[n:]
+	// Signed, version 4, from a MultiAddress::Id.
+	if len(body) < 2+32+1 || body[0] != 0x84 || body[1] != 0x00 {
+		return nil, fmt.Errorf("%w: not signed by an account", ErrAvailInvalidExtrinsic)
+	}
+	call := &availCall{}
+	copy(call.signer[:], body[2:])
+	body = body[2+32:]
+
+	// MultiSignature: Ed25519 and Sr25519 are 64 bytes, Ecdsa is 65.
+	signatureSize := 64
+	switch body[0] {
+	case 0x00, 0x01:
+	case 0x02:
+		signatureSize = 65
+	default:
+		return nil, fmt.Errorf("%w: signature type %d", ErrAvailInvalidExtrinsic, body[0])
+	}
+	if len(body) < 1+signatureSize+1 {
+		return nil, fmt.Errorf("%w: truncated signature", ErrAvailInvalidExtrinsic)
+	}
+	body = body[1+signatureSize:]
+	// The era is one byte if immortal and two otherwise.
+	eraSize := 1
+	if body[0] != 0x00 {
+		eraSize = 2
+	}
+	if len(body) < eraSize {
+		return nil, fmt.Errorf("%w: truncated era", ErrAvailInvalidExtrinsic)
+	}
+	body = body[eraSize:]
+
+	var extra [3]uint64 // Nonce, tip and app ID.
+	for i := range extra {
+		v, n := readCompact(body)
+		if n == 0 {
+			return nil, fmt.Errorf("%w: truncated extra", ErrAvailInvalidExtrinsic)
+		}
+		extra[i] = v
+		body = body[n:]
+	}
+	call.nonce, call.appID = extra[0], uint32(extra[2])
+	if len(body) < 2 {
+		return nil, fmt.Errorf("%w: truncated call", ErrAvailInvalidExtrinsic)
+	}
+	call.pallet, call.method, call.args = body[0], body[1], body[2:]
+	return call, nil
+}
+
+// decodeAvailData returns the data of a submit_data extrinsic.
+func decodeAvailData(encoded []byte) ([]byte, bool) {
+	call, err := decodeAvailExtrinsic(encoded)
+	if err != nil || call.pallet != AvailDataAvailabilityPallet || call.method != AvailSubmitDataCall {
+		return nil, false
+	}
+	return call.bytesArg()
+}
+
+// appendCompact appends the SCALE compact encoding of [v].
+func appendCompact(b []byte, v uint64) []byte {
+	switch {
+	case v < 1<<6:
+		return append(b, byte(v<<2))
+	case v < 1<<14:
+		return binary.LittleEndian.AppendUint16(b, uint16(v<<2|0b01))
+	case v < 1<<30:
+		return binary.LittleEndian.AppendUint32(b, uint32(v<<2|0b10))
+	default:
+		return append(append(b, (8-4)<<2|0b11), binary.LittleEndian.AppendUint64(nil, v)...)
+	}
+}
+
+// readCompact decodes the compact integer prefixing [b] and returns it with
+// its encoded size, which is 0 if [b] is truncated or holds more than 64
+// bits.
+func readCompact(b []byte) (uint64, int) {
+	if len(b) == 0 {
+		return 0, 0
+	}
+	switch b[0] & 0b11 {
+	case 0b00:
+		return uint64(b[0] >> 2), 1
+	case 0b01:
+		if len(b) < 2 {
+			return 0, 0
+		}
+		return uint64(binary.LittleEndian.Uint16(b) >> 2), 2
+	case 0b10:
+		if len(b) < 4 {
+			return 0, 0
+		}
+		return uint64(binary.LittleEndian.Uint32(b) >> 2), 4
+	default:
+		// Big integer mode, the upper 6 bits hold the byte length minus 4.
+		size := int(b[0]>>2) + 4
+		if size > 8 || len(b) < 1+size {
+			return 0, 0
+		}
+		var v uint64
+		for i := size - 1; i >= 0; i-- {
+			v = v<<8 | uint64(b[1+i])
+		}
+		return v, 1 + size
+	}
+}
+
+// availMerkleProof returns the root of the binary Merkle tree over [leaves],
+// where a node without sibling is promoted unchanged, and the siblings
+// proving the leaf at [index].
+func availMerkleProof(leaves []common.Hash, index int) (common.Hash, []common.Hash) {
+	if len(leaves) == 0 {
+		return common.Hash{}, nil
+	}
+	var (
+		level    = leaves
+		siblings []common.Hash
+	)
+	for len(level) > 1 {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, crypto.Keccak256Hash(level[i][:], level[i+1][:]))
+		}
+		switch {
+		case index%2 == 1:
+			siblings = append(siblings, level[index-1])
+		case index+1 < len(level):
+			siblings = append(siblings, level[index+1])
+		}
+		level = next
+		index /= 2
+	}
+	return level[0], siblings
+}
+
+// availSubscriptions maps the substrate subscription methods to the
+// subscribe and unsubscribe methods of [rpc.Server], which takes the name of
+// the subscription as first parameter.
+var availSubscriptions = map[string]struct {
+	method string
+	name   string
+}{
+	"author_submitAndWatchExtrinsic": {method: "author_subscribe", name: "submitAndWatchExtrinsic"},
+	"author_unwatchExtrinsic":        {method: "author_unsubscribe"},
+}
+
+// rewriteAvailSubscription rewrites a call to a substrate subscription method
+// into the form served by [rpc.Server]. Other messages are returned as is.
+func rewriteAvailSubscription(msg []byte) []byte {
+	var call map[string]json.RawMessage
+	if err := json.Unmarshal(msg, &call); err != nil {
+		return msg
+	}
+	var method string
+	if err := json.Unmarshal(call["method"], &method); err != nil {
+		return msg
+	}
+	sub, ok := availSubscriptions[method]
+	if !ok {
+		return msg
+	}
+	var params []json.RawMessage
+	if err := json.Unmarshal(call["params"], &params); err != nil {
+		return msg
+	}
+	if sub.name != "" {
+		name, _ := json.Marshal(sub.name)
+		params = append([]json.RawMessage{name}, params...)
+	}
+	call["method"], _ = json.Marshal(sub.method)
+	call["params"], _ = json.Marshal(params)
+	rewritten, err := json.Marshal(call)
+	if err != nil {
+		return msg
+	}
+	return rewritten
+}
+
+// serveSubstrate serves [server] over websocket for upgrade requests, with
+// the substrate subscription methods, and over HTTP otherwise.
+func serveSubstrate(server *rpc.Server) http.Handler {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(*http.Request) bool { return true },
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			server.ServeHTTP(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		codec := rpc.NewFuncCodec(
+			conn,
+			func(v any, _ bool) error {
+				return conn.WriteJSON(v)
+			},
+			func(v any) error {
+				_, msg, err := conn.ReadMessage()
+				if err != nil {
+					return err
+				}
+				return json.Unmarshal(rewriteAvailSubscription(msg), v)
+			},
+		)
+		server.ServeCodec(codec, 0)
+	})
+}
+
+// availAuthorAPI is the "author" namespace of [Avail].
+type availAuthorAPI struct {
+	a *Avail
+}
+
+func (api *availAuthorAPI) SubmitExtrinsic(extrinsic hexutil.Bytes) (common.Hash, error) {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	if _, err := api.a.include(extrinsic); err != nil {
+		return common.Hash{}, err
+	}
+	return blake2b.Sum256(extrinsic), nil
+}
+
+// SubmitAndWatchExtrinsic includes [extrinsic] and notifies that it is ready,
+// then in its block, then finalized.
+func (api *availAuthorAPI) SubmitAndWatchExtrinsic(ctx context.Context, extrinsic hexutil.Bytes) (*rpc.Subscription, error) {
+	notifier, ok := rpc.NotifierFromContext(ctx)
+	if !ok {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	block, err := api.a.include(extrinsic)
+	if err != nil {
+		return nil, err
+	}
+	// Notifications are sent once the subscription is returned.
+	sub := notifier.CreateSubscription()
+	_ = notifier.Notify(sub.ID, "ready")
+	_ = notifier.Notify(sub.ID, map[string]common.Hash{"inBlock": block.header.hash()})
+	api.a.watchers = append(api.a.watchers, availWatcher{
+		notifier: notifier,
+		id:       sub.ID,
+		number:   uint64(block.header.Number),
+	})
+	api.a.notifyFinalized()
+	return sub, nil
+}
+
+// availChainAPI is the "chain" namespace of [Avail].
+type availChainAPI struct {
+	a *Avail
+}
+
+type availSignedBlock struct {
+	Block struct {
+		Header     *availHeader    `json:"header"`
+		Extrinsics []hexutil.Bytes `json:"extrinsics"`
+	} `json:"block"`
+	Justifications json.RawMessage `json:"justifications"`
+}
+
+func (api *availChainAPI) GetBlock(hash *common.Hash) (*availSignedBlock, error) {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	block, err := api.a.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	signed := &availSignedBlock{Justifications: json.RawMessage("null")}
+	signed.Block.Header = &block.header
+	signed.Block.Extrinsics = make([]hexutil.Bytes, len(block.extrinsics))
+	for i, ext := range block.extrinsics {
+		signed.Block.Extrinsics[i] = ext.encoded
+	}
+	return signed, nil
+}
+
+func (api *availChainAPI) GetHeader(hash *common.Hash) (*availHeader, error) {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	block, err := api.a.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	return &block.header, nil
+}
+
+// availBlockNumber is a block number given either as a JSON number or as a
+// hex string.
+type availBlockNumber uint64
+
+func (n *availBlockNumber) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var v hexutil.Uint64
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*n = availBlockNumber(v)
+		return nil
+	}
+	var v uint64
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*n = availBlockNumber(v)
+	return nil
+}
+
+func (api *availChainAPI) GetBlockHash(number *availBlockNumber) (common.Hash, error) {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	if number == nil {
+		return api.a.blocks[len(api.a.blocks)-1].header.hash(), nil
+	}
+	if int(*number) >= len(api.a.blocks) {
+		return common.Hash{}, fmt.Errorf("%w: %d", ErrAvailBlockNotFound, *number)
+	}
+	return api.a.blocks[*number].header.hash(), nil
+}
+
+func (api *availChainAPI) GetFinalizedHead() common.Hash {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	return api.a.blocks[api.a.finalized()].header.hash()
+}
+
+// availKateAPI is the "kate" namespace of [Avail].
+type availKateAPI struct {
+	a *Avail
+}
+
+type availDataProofResponse struct {
+	DataProof actions.AvailDataProof `json:"dataProof"`
+	Message   json.RawMessage        `json:"message"`
+}
+
+func (api *availKateAPI) QueryDataProof(index uint32, hash *common.Hash) (*availDataProofResponse, error) {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	block, err := api.a.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	if int(index) >= len(block.extrinsics) {
+		return nil, fmt.Errorf("%w: %d", ErrAvailExtrinsicNotFound, index)
+	}
+	leaf := block.extrinsics[index].leaf
+	if leaf < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrAvailNotDataExtrinsic, index)
+	}
+	_, siblings := availMerkleProof(block.leaves, leaf)
+	return &availDataProofResponse{
+		DataProof: actions.AvailDataProof{
+			Roots: actions.AvailDataRoots{
+				DataRoot: block.header.Extension.V3.Commitment.DataRoot,
+				BlobRoot: block.blobRoot,
+			},
+			Proof:          siblings,
+			NumberOfLeaves: uint32(len(block.leaves)),
+			LeafIndex:      uint32(leaf),
+			Leaf:           block.leaves[leaf],
+		},
+		Message: json.RawMessage("null"),
+	}, nil
+}
+
+// availStateAPI is the "state" namespace of [Avail].
+type availStateAPI struct {
+	a *Avail
+}
+
+// Call runs the runtime API [method]. Only the length fee query is
+// supported, at [AvailLengthFeePerByte].
+func (*availStateAPI) Call(method string, args hexutil.Bytes, _ *common.Hash) (hexutil.Bytes, error) {
+	if method != actions.AvailLengthToFeeMethod || len(args) != 4 {
+		return nil, fmt.Errorf("%w: %s", ErrAvailUnknownRuntimeAPI, method)
+	}
+	fee := uint64(binary.LittleEndian.Uint32(args)) * AvailLengthFeePerByte
+	// The fee is a little endian u128.
+	return binary.LittleEndian.AppendUint64(binary.LittleEndian.AppendUint64(nil, fee), 0), nil
+}
+
+func (api *availStateAPI) GetMetadata(_ *common.Hash) hexutil.Bytes {
+	return api.a.encodedMetadata
+}
+
+type availRuntimeVersion struct {
+	SpecName           string  `json:"specName"`
+	ImplName           string  `json:"implName"`
+	AuthoringVersion   uint32  `json:"authoringVersion"`
+	SpecVersion        uint32  `json:"specVersion"`
+	ImplVersion        uint32  `json:"implVersion"`
+	APIs               [][]any `json:"apis"`
+	TransactionVersion uint32  `json:"transactionVersion"`
+	StateVersion       uint32  `json:"stateVersion"`
+}
+
+func (*availStateAPI) GetRuntimeVersion(_ *common.Hash) *availRuntimeVersion {
+	return &availRuntimeVersion{
+		SpecName:           "avail",
+		ImplName:           "avail",
+		AuthoringVersion:   1,
+		SpecVersion:        availSpecVersion,
+		APIs:               [][]any{},
+		TransactionVersion: availTransactionVersion,
+		StateVersion:       1,
+	}
+}
+
+// GetStorage returns the value under [key] at the block with [hash], or
+// null if there is none.
+func (api *availStateAPI) GetStorage(key hexutil.Bytes, hash *common.Hash) (*hexutil.Bytes, error) {
+	api.a.lock.Lock()
+	defer api.a.lock.Unlock()
+
+	block, err := api.a.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	value := api.a.storage(key, block)
+	if value == nil {
+		return nil, nil
+	}
+	return (*hexutil.Bytes)(&value), nil
+}
@@ -0,0 +1,308 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package datest
+
+import (
+	"fmt"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+)
+
+const (
+	// AvailSystemPallet indexes the System pallet in the Avail runtime.
+	AvailSystemPallet = 0
+	// AvailCreateApplicationKeyCall indexes the create_application_key call
+	// of the DataAvailability pallet.
+	AvailCreateApplicationKeyCall = 0
+
+	availSpecVersion        = 39
+	availTransactionVersion = 1
+)
+
+// Indices of the events deposited by [Avail].
+const (
+	availExtrinsicSuccessEvent      = 0
+	availApplicationKeyCreatedEvent = 0
+	availDataSubmittedEvent         = 1
+)
+
+// availRegistry builds the portable type registry of the runtime metadata.
+type availRegistry struct {
+	types []types.PortableTypeV14
+}
+
+func (r *availRegistry) add(path []string, def types.Si1TypeDef) types.Si1LookupTypeID {
+	id := types.NewSi1LookupTypeIDFromUInt(uint64(len(r.types)))
+	typ := types.Si1Type{Def: def}
+	for _, segment := range path {
+		typ.Path = append(typ.Path, types.NewText(segment))
+	}
+	r.types = append(r.types, types.PortableTypeV14{ID: id, Type: typ})
+	return id
+}
+
+func (r *availRegistry) primitive(p types.Si0TypeDefPrimitive) types.Si1LookupTypeID {
+	return r.add(nil, types.Si1TypeDef{
+		IsPrimitive: true,
+		Primitive:   types.Si1TypeDefPrimitive{Si0TypeDefPrimitive: p},
+	})
+}
+
+func (r *availRegistry) composite(path []string, fields ...types.Si1Field) types.Si1LookupTypeID {
+	return r.add(path, types.Si1TypeDef{
+		IsComposite: true,
+		Composite:   types.Si1TypeDefComposite{Fields: fields},
+	})
+}
+
+func (r *availRegistry) variant(path []string, variants ...types.Si1Variant) types.Si1LookupTypeID {
+	return r.add(path, types.Si1TypeDef{
+		IsVariant: true,
+		Variant:   types.Si1TypeDefVariant{Variants: variants},
+	})
+}
+
+func (r *availRegistry) sequence(of types.Si1LookupTypeID) types.Si1LookupTypeID {
+	return r.add(nil, types.Si1TypeDef{
+		IsSequence: true,
+		Sequence:   types.Si1TypeDefSequence{Type: of},
+	})
+}
+
+func (r *availRegistry) array(size uint32, of types.Si1LookupTypeID) types.Si1LookupTypeID {
+	return r.add(nil, types.Si1TypeDef{
+		IsArray: true,
+		Array:   types.Si1TypeDefArray{Len: types.NewU32(size), Type: of},
+	})
+}
+
+func (r *availRegistry) compact(of types.Si1LookupTypeID) types.Si1LookupTypeID {
+	return r.add(nil, types.Si1TypeDef{
+		IsCompact: true,
+		Compact:   types.Si1TypeDefCompact{Type: of},
+	})
+}
+
+func (r *availRegistry) tuple(of ...types.Si1LookupTypeID) types.Si1LookupTypeID {
+	return r.add(nil, types.Si1TypeDef{
+		IsTuple: true,
+		Tuple:   of,
+	})
+}
+
+func availField(name string, typ types.Si1LookupTypeID) types.Si1Field {
+	return types.Si1Field{
+		HasName: name != "",
+		Name:    types.NewText(name),
+		Type:    typ,
+	}
+}
+
+func availVariant(name string, index uint8, fields ...types.Si1Field) types.Si1Variant {
+	return types.Si1Variant{
+		Name:   types.NewText(name),
+		Fields: fields,
+		Index:  types.NewU8(index),
+	}
+}
+
+func availStorageMap(name string, key, value types.Si1LookupTypeID, optional bool) types.StorageEntryMetadataV14 {
+	return types.StorageEntryMetadataV14{
+		Name:     types.NewText(name),
+		Modifier: types.StorageFunctionModifierV0{IsOptional: optional, IsDefault: !optional},
+		Type: types.StorageEntryTypeV14{
+			IsMap: true,
+			AsMap: types.MapTypeV14{
+				Hashers: []types.StorageHasherV10{{IsBlake2_128Concat: true}},
+				Key:     key,
+				Value:   value,
+			},
+		},
+		Fallback: types.Bytes{},
+	}
+}
+
+func availStoragePlain(name string, value types.Si1LookupTypeID) types.StorageEntryMetadataV14 {
+	return types.StorageEntryMetadataV14{
+		Name:     types.NewText(name),
+		Modifier: types.StorageFunctionModifierV0{IsDefault: true},
+		Type: types.StorageEntryTypeV14{
+			IsPlainType: true,
+			AsPlainType: value,
+		},
+		Fallback: types.Bytes{0x00},
+	}
+}
+
+// availMetadata returns the V14 metadata of a minimal Avail runtime: the
+// System pallet with its Account and Events storage, and the
+// DataAvailability pallet with its calls, events and app key storage. It is
+// enough for the SDK to build, sign and watch extrinsics.
+func availMetadata() (*types.Metadata, []byte, error) {
+	r := &availRegistry{}
+	var (
+		u8   = r.primitive(types.IsU8)
+		u32  = r.primitive(types.IsU32)
+		u64  = r.primitive(types.IsU64)
+		u128 = r.primitive(types.IsU128)
+		unit = r.tuple()
+
+		bytes32   = r.array(32, u8)
+		accountID = r.composite([]string{"sp_core", "crypto", "AccountId32"}, availField("", bytes32))
+		h256      = r.composite([]string{"primitive_types", "H256"}, availField("", bytes32))
+		byteVec   = r.sequence(u8)
+		boundedU8 = r.composite([]string{"bounded_collections", "bounded_vec", "BoundedVec"}, availField("", byteVec))
+		appID     = r.composite([]string{"avail_core", "AppId"}, availField("", r.compact(u32)))
+
+		accountData = r.composite([]string{"pallet_balances", "types", "AccountData"},
+			availField("free", u128),
+			availField("reserved", u128),
+			availField("frozen", u128),
+			availField("flags", u128),
+		)
+		accountInfo = r.composite([]string{"frame_system", "AccountInfo"},
+			availField("nonce", u32),
+			availField("consumers", u32),
+			availField("providers", u32),
+			availField("sufficients", u32),
+			availField("data", accountData),
+		)
+		appKeyInfo = r.composite([]string{"da_control", "pallet", "AppKeyInfo"},
+			availField("owner", accountID),
+			availField("id", appID),
+		)
+
+		daCall = r.variant([]string{"da_control", "pallet", "Call"},
+			availVariant("create_application_key", AvailCreateApplicationKeyCall, availField("key", boundedU8)),
+			availVariant("submit_data", AvailSubmitDataCall, availField("data", boundedU8)),
+		)
+
+		weight = r.composite([]string{"sp_weights", "weight_v2", "Weight"},
+			availField("ref_time", r.compact(u64)),
+			availField("proof_size", r.compact(u64)),
+		)
+		dispatchInfo = r.composite([]string{"frame_support", "dispatch", "DispatchInfo"},
+			availField("weight", weight),
+			availField("class", r.variant([]string{"frame_support", "dispatch", "DispatchClass"},
+				availVariant("Normal", 0),
+				availVariant("Operational", 1),
+				availVariant("Mandatory", 2),
+			)),
+			availField("pays_fee", r.variant([]string{"frame_support", "dispatch", "Pays"},
+				availVariant("Yes", 0),
+				availVariant("No", 1),
+			)),
+		)
+		systemEvent = r.variant([]string{"frame_system", "pallet", "Event"},
+			availVariant("ExtrinsicSuccess", availExtrinsicSuccessEvent, availField("dispatch_info", dispatchInfo)),
+		)
+		daEvent = r.variant([]string{"da_control", "pallet", "Event"},
+			availVariant("ApplicationKeyCreated", availApplicationKeyCreatedEvent,
+				availField("key", boundedU8),
+				availField("owner", accountID),
+				availField("id", appID),
+			),
+			availVariant("DataSubmitted", availDataSubmittedEvent,
+				availField("who", accountID),
+				availField("data_hash", h256),
+			),
+		)
+		runtimeEvent = r.variant([]string{"da_runtime", "RuntimeEvent"},
+			availVariant("System", AvailSystemPallet, availField("", systemEvent)),
+			availVariant("DataAvailability", AvailDataAvailabilityPallet, availField("", daEvent)),
+		)
+		eventRecord = r.composite([]string{"frame_system", "EventRecord"},
+			availField("phase", r.variant([]string{"frame_system", "Phase"},
+				availVariant("ApplyExtrinsic", 0, availField("", u32)),
+				availVariant("Finalization", 1),
+				availVariant("Initialization", 2),
+			)),
+			availField("event", runtimeEvent),
+			availField("topics", r.sequence(h256)),
+		)
+		eventRecords = r.sequence(eventRecord)
+
+		extrinsic = r.composite([]string{"sp_runtime", "generic", "unchecked_extrinsic", "UncheckedExtrinsic"},
+			availField("", byteVec),
+		)
+		runtime = r.composite([]string{"da_runtime", "Runtime"})
+	)
+
+	eras := []types.Si1Variant{availVariant("Immortal", 0)}
+	for i := 1; i < 256; i++ {
+		eras = append(eras, availVariant(fmt.Sprintf("Mortal%d", i), uint8(i), availField("", u8)))
+	}
+	era := r.variant([]string{"sp_runtime", "generic", "era", "Era"}, eras...)
+
+	// The extra of each signed extension, in the order they are encoded after
+	// the signature, and what they add to the signed payload.
+	extensions := []types.SignedExtensionMetadataV14{
+		{Identifier: "CheckNonZeroSender", Type: unit, AdditionalSigned: unit},
+		{Identifier: "CheckSpecVersion", Type: unit, AdditionalSigned: u32},
+		{Identifier: "CheckTxVersion", Type: unit, AdditionalSigned: u32},
+		{Identifier: "CheckGenesis", Type: unit, AdditionalSigned: h256},
+		{Identifier: "CheckMortality", Type: era, AdditionalSigned: h256},
+		{Identifier: "CheckNonce", Type: r.compact(u32), AdditionalSigned: unit},
+		{Identifier: "CheckWeight", Type: unit, AdditionalSigned: unit},
+		{Identifier: "ChargeTransactionPayment", Type: r.compact(u128), AdditionalSigned: unit},
+		{Identifier: "CheckAppId", Type: appID, AdditionalSigned: unit},
+	}
+
+	metadata := types.Metadata{
+		MagicNumber: types.MagicNumber,
+		Version:     14,
+		AsMetadataV14: types.MetadataV14{
+			Lookup: types.PortableRegistryV14{Types: r.types},
+			Pallets: []types.PalletMetadataV14{
+				{
+					Name:       "System",
+					HasStorage: true,
+					Storage: types.StorageMetadataV14{
+						Prefix: "System",
+						Items: []types.StorageEntryMetadataV14{
+							availStorageMap("Account", accountID, accountInfo, false),
+							availStoragePlain("Events", eventRecords),
+						},
+					},
+					HasEvents: true,
+					Events:    types.EventMetadataV14{Type: systemEvent},
+					Index:     AvailSystemPallet,
+				},
+				{
+					Name:       "DataAvailability",
+					HasStorage: true,
+					Storage: types.StorageMetadataV14{
+						Prefix: "DataAvailability",
+						Items: []types.StorageEntryMetadataV14{
+							availStorageMap("AppKeys", boundedU8, appKeyInfo, true),
+							availStoragePlain("NextAppId", appID),
+						},
+					},
+					HasCalls:  true,
+					Calls:     types.FunctionMetadataV14{Type: daCall},
+					HasEvents: true,
+					Events:    types.EventMetadataV14{Type: daEvent},
+					Index:     AvailDataAvailabilityPallet,
+				},
+			},
+			Extrinsic: types.ExtrinsicV14{
+				Type:             extrinsic,
+				Version:          4,
+				SignedExtensions: extensions,
+			},
+			Type: runtime,
+		},
+	}
+	encoded, err := codec.Encode(metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Decoding builds the lookup used to find calls and storage entries.
+	var decoded types.Metadata
+	if err := codec.Decode(encoded, &decoded); err != nil {
+		return nil, nil, err
+	}
+	return &decoded, encoded, nil
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package datest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/retriever"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/registry/state"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+func TestAvailDataProofs(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := NewAvail(t)
+	data := [][]byte{
+		[]byte("first"),
+		bytes.Repeat([]byte{0x02}, 100),
+		bytes.Repeat([]byte{0x03}, 20_000),
+	}
+	submissions := fake.Submit(7, data...)
+	require.Len(submissions, len(data))
+
+	client, err := rpc.DialContext(ctx, fake.URL)
+	require.NoError(err)
+	t.Cleanup(client.Close)
+
+	for i, submission := range submissions {
+		var block availSignedBlock
+		require.NoError(client.CallContext(ctx, &block, "chain_getBlock", submission.BlockHash.Hex()))
+		encoded := block.Block.Extrinsics[submission.Index]
+		decoded, ok := decodeAvailData(encoded)
+		require.True(ok)
+		require.Equal(data[i], decoded)
+
+		var resp availDataProofResponse
+		require.NoError(client.CallContext(ctx, &resp, "kate_queryDataProof", submission.Index, submission.BlockHash.Hex()))
+		dataRoot := block.Block.Header.Extension.V3.Commitment.DataRoot
+		require.NoError(actions.VerifyAvailDataProof(&resp.DataProof, data[i], dataRoot))
+		require.ErrorIs(actions.VerifyAvailDataProof(&resp.DataProof, []byte("other"), dataRoot), actions.ErrAvailLeafMismatch)
+	}
+
+	var finalized, head availSignedBlock
+	var finalizedHash string
+	require.NoError(client.CallContext(ctx, &finalizedHash, "chain_getFinalizedHead"))
+	require.NoError(client.CallContext(ctx, &finalized, "chain_getBlock", finalizedHash))
+	require.NoError(client.CallContext(ctx, &head, "chain_getBlock"))
+	require.Equal(head.Block.Header.Number-AvailFinalityDepth, finalized.Block.Header.Number)
+}
+
+func TestAvailFees(t *testing.T) {
+	require := require.New(t)
+
+	fake := NewAvail(t)
+	for _, size := range []int{1, 100, 20_000, 1 << 20} {
+		data := bytes.Repeat([]byte{0x01}, size)
+		extrinsicSize := actions.AvailDataExtrinsicSize(size)
+		require.GreaterOrEqual(extrinsicSize, len(EncodeAvailDataExtrinsic(fake.Signer, 1_000_000, data)))
+
+		fee, err := actions.EstimateAvailFee(context.Background(), fake.URL, size)
+		require.NoError(err)
+		require.Equal(uint64(extrinsicSize)*AvailLengthFeePerByte, fee.Uint64())
+	}
+}
+
+func TestAvailSubstrateRuntime(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := NewAvail(t)
+	api, err := gsrpc.NewSubstrateAPI(fake.URL)
+	require.NoError(err)
+	t.Cleanup(api.Client.Close)
+
+	meta, err := api.RPC.State.GetMetadataLatest()
+	require.NoError(err)
+	submitData, err := meta.FindCallIndex("DataAvailability.submit_data")
+	require.NoError(err)
+	require.Equal(types.CallIndex{SectionIndex: AvailDataAvailabilityPallet, MethodIndex: AvailSubmitDataCall}, submitData)
+	createKey, err := meta.FindCallIndex("DataAvailability.create_application_key")
+	require.NoError(err)
+	require.Equal(types.CallIndex{SectionIndex: AvailDataAvailabilityPallet, MethodIndex: AvailCreateApplicationKeyCall}, createKey)
+
+	version, err := api.RPC.State.GetRuntimeVersionLatest()
+	require.NoError(err)
+	require.Equal(types.U32(availSpecVersion), version.SpecVersion)
+	genesis, err := api.RPC.Chain.GetBlockHash(0)
+	require.NoError(err)
+	require.NotEqual(types.Hash{}, genesis)
+
+	// The extrinsic is reported in its block, then finalized once a block is
+	// built on top of it.
+	data := []byte("watched")
+	statuses := make(chan types.ExtrinsicStatus, 3)
+	sub, err := api.Client.Subscribe(ctx, "author", "submitAndWatchExtrinsic", "unwatchExtrinsic", "extrinsicUpdate",
+		statuses, hexutil.Encode(EncodeAvailDataExtrinsic(fake.Signer, 3, data)))
+	require.NoError(err)
+	require.True((<-statuses).IsReady)
+	inBlock := <-statuses
+	require.True(inBlock.IsInBlock)
+	fake.BuildBlock()
+	finalized := <-statuses
+	require.True(finalized.IsFinalized)
+	require.Equal(inBlock.AsInBlock, finalized.AsFinalized)
+	sub.Unsubscribe()
+
+	var block availSignedBlock
+	require.NoError(api.Client.Call(&block, "chain_getBlock", inBlock.AsInBlock.Hex()))
+	require.Len(block.Block.Extrinsics, 1)
+	decoded, ok := decodeAvailData(block.Block.Extrinsics[0])
+	require.True(ok)
+	require.Equal(data, decoded)
+
+	// The nonce counts the included extrinsics of the account.
+	key, err := types.CreateStorageKey(meta, "System", "Account", fake.Signer[:])
+	require.NoError(err)
+	var account types.AccountInfo
+	ok, err = api.RPC.State.GetStorageLatest(key, &account)
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(types.U32(1), account.Nonce)
+	var txHash common.Hash
+	err = api.Client.Call(&txHash, "author_submitExtrinsic", hexutil.Encode(EncodeAvailDataExtrinsic(fake.Signer, 3, data)))
+	require.ErrorContains(err, ErrAvailStaleNonce.Error())
+
+	events, err := retriever.NewDefaultEventRetriever(state.NewEventProvider(api.RPC.State), api.RPC.State)
+	require.NoError(err)
+	records, err := events.GetEvents(inBlock.AsInBlock)
+	require.NoError(err)
+	require.Len(records, 2)
+	require.Equal("DataAvailability.DataSubmitted", records[0].Name)
+	require.Equal("System.ExtrinsicSuccess", records[1].Name)
+}
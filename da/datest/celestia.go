@@ -0,0 +1,154 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package datest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+var (
+	ErrCelestiaEmptySubmission = errors.New("no blobs submitted")
+	ErrCelestiaFutureHeight    = errors.New("height is from the future")
+	ErrCelestiaBlobNotFound    = errors.New("blob: not found")
+)
+
+// Celestia is a fake celestia-node serving the blob module of its JSON-RPC
+// API. Every submission is included in a new block. Commitments are computed
+// like a node would, but proofs are empty: [blob.Included] only checks that
+// the blob exists.
+type Celestia struct {
+	// URL is the HTTP JSON-RPC endpoint. Any auth token is accepted.
+	URL string
+
+	lock   sync.Mutex
+	height uint64
+	blobs  map[uint64][]*blob.Blob
+}
+
+// NewCelestia starts a fake Celestia node. It is stopped when [t] completes.
+func NewCelestia(t testing.TB) *Celestia {
+	c := &Celestia{
+		blobs: make(map[uint64][]*blob.Blob),
+	}
+	server := jsonrpc.NewServer()
+	server.Register("blob", &celestiaBlobAPI{c: c})
+	server.Register("node", &celestiaNodeAPI{})
+	httpServer := httptest.NewServer(server)
+	c.URL = httpServer.URL
+	t.Cleanup(httpServer.Close)
+	return c
+}
+
+// Height returns the height of the last block.
+func (c *Celestia) Height() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.height
+}
+
+func (c *Celestia) find(height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Blob, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if height > c.height {
+		return nil, fmt.Errorf("%w: %d", ErrCelestiaFutureHeight, height)
+	}
+	for _, b := range c.blobs[height] {
+		if blobNamespace(b).Equals(namespace) && b.Commitment.Equal(commitment) {
+			return b, nil
+		}
+	}
+	return nil, ErrCelestiaBlobNotFound
+}
+
+func blobNamespace(b *blob.Blob) share.Namespace {
+	return share.Namespace(b.Namespace().Bytes())
+}
+
+// celestiaBlobAPI is the "blob" module of [Celestia].
+type celestiaBlobAPI struct {
+	c *Celestia
+}
+
+func (api *celestiaBlobAPI) Submit(_ context.Context, blobs []*blob.Blob, _ *blob.SubmitOptions) (uint64, error) {
+	if len(blobs) == 0 {
+		return 0, ErrCelestiaEmptySubmission
+	}
+	// The commitments are recomputed rather than trusted.
+	included := make([]*blob.Blob, len(blobs))
+	for i, b := range blobs {
+		namespace, err := share.NamespaceFromBytes(b.Namespace().Bytes())
+		if err == nil {
+			included[i], err = blob.NewBlob(uint8(b.ShareVersion), namespace, b.Data)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("invalid blob %d: %w", i, err)
+		}
+	}
+
+	api.c.lock.Lock()
+	defer api.c.lock.Unlock()
+
+	api.c.height++
+	api.c.blobs[api.c.height] = included
+	return api.c.height, nil
+}
+
+func (api *celestiaBlobAPI) Get(_ context.Context, height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Blob, error) {
+	return api.c.find(height, namespace, commitment)
+}
+
+func (api *celestiaBlobAPI) GetAll(_ context.Context, height uint64, namespaces []share.Namespace) ([]*blob.Blob, error) {
+	api.c.lock.Lock()
+	defer api.c.lock.Unlock()
+
+	if height > api.c.height {
+		return nil, fmt.Errorf("%w: %d", ErrCelestiaFutureHeight, height)
+	}
+	var blobs []*blob.Blob
+	for _, b := range api.c.blobs[height] {
+		for _, namespace := range namespaces {
+			if blobNamespace(b).Equals(namespace) {
+				blobs = append(blobs, b)
+				break
+			}
+		}
+	}
+	if len(blobs) == 0 {
+		return nil, ErrCelestiaBlobNotFound
+	}
+	return blobs, nil
+}
+
+func (api *celestiaBlobAPI) GetProof(_ context.Context, height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Proof, error) {
+	if _, err := api.c.find(height, namespace, commitment); err != nil {
+		return nil, err
+	}
+	return &blob.Proof{}, nil
+}
+
+func (api *celestiaBlobAPI) Included(_ context.Context, height uint64, namespace share.Namespace, _ *blob.Proof, commitment blob.Commitment) (bool, error) {
+	_, err := api.c.find(height, namespace, commitment)
+	if errors.Is(err, ErrCelestiaBlobNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// celestiaNodeAPI is the "node" module of [Celestia].
+type celestiaNodeAPI struct{}
+
+func (*celestiaNodeAPI) Ready(context.Context) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,295 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package datest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/common"
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/Layr-Labs/eigenda/api/grpc/retriever"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+const (
+	// EigenDAAdversaryThreshold and EigenDAConfirmationThreshold are the
+	// thresholds every quorum is dispersed with.
+	EigenDAAdversaryThreshold    = 33
+	EigenDAConfirmationThreshold = 55
+)
+
+// EigenDARequiredQuorums are dispersed to on top of the requested quorums.
+var EigenDARequiredQuorums = []uint32{0, 1}
+
+var (
+	ErrEigenDAEmptyBlob       = errors.New("blob is empty")
+	ErrEigenDAUnauthenticated = errors.New("missing authentication data")
+)
+
+type eigenDABlob struct {
+	data       []byte
+	quorums    []uint32
+	polls      int
+	batchIndex uint32
+}
+
+// EigenDA is a fake EigenDA disperser and retriever served over gRPC.
+// Dispersed blobs move from processing to confirmed and then finalized, one
+// step every status poll, and are signed by every operator of their quorums.
+// Authentication data is accepted without being checked.
+type EigenDA struct {
+	disperser.UnimplementedDisperserServer
+
+	// Host and Port locate both services.
+	Host string
+	Port string
+
+	lock    sync.Mutex
+	blobs   map[string]*eigenDABlob
+	batches map[string]string
+	nonce   uint32
+}
+
+// NewEigenDA starts a fake EigenDA disperser and retriever. They are stopped
+// when [t] completes.
+func NewEigenDA(t testing.TB) *EigenDA {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &EigenDA{
+		Host:    host,
+		Port:    port,
+		blobs:   make(map[string]*eigenDABlob),
+		batches: make(map[string]string),
+	}
+
+	server := grpc.NewServer()
+	disperser.RegisterDisperserServer(server, e)
+	retriever.RegisterRetrieverServer(server, &retrieverServer{e: e})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+	return e
+}
+
+// DisperserConfig returns the configuration of a client of the disperser.
+func (e *EigenDA) DisperserConfig() actions.EigenDADisperserConfig {
+	config := actions.NewDefaultEigenDADisperserConfig()
+	config.Host = e.Host
+	config.Port = e.Port
+	config.UseTLS = false
+	config.PollInterval = 10 * time.Millisecond
+	config.StatusTimeout = 5 * time.Second
+	return config
+}
+
+// RetrieverConfig returns the configuration of a client of the retriever.
+func (e *EigenDA) RetrieverConfig() actions.EigenDARetrieverConfig {
+	config := actions.NewDefaultEigenDARetrieverConfig()
+	config.Host = e.Host
+	config.Port = e.Port
+	config.UseTLS = false
+	return config
+}
+
+func (e *EigenDA) disperse(request *disperser.DisperseBlobRequest) (*disperser.DisperseBlobReply, error) {
+	if len(request.GetData()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, ErrEigenDAEmptyBlob.Error())
+	}
+	quorums := slices.Clone(EigenDARequiredQuorums)
+	for _, quorum := range request.GetCustomQuorumNumbers() {
+		if !slices.Contains(quorums, quorum) {
+			quorums = append(quorums, quorum)
+		}
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.nonce++
+	h := sha256.New()
+	h.Write(request.GetData())
+	_ = binary.Write(h, binary.BigEndian, e.nonce)
+	requestID := h.Sum(nil)
+	batchHeaderHash := sha256.Sum256(requestID)
+
+	e.blobs[string(requestID)] = &eigenDABlob{
+		data:       slices.Clone(request.GetData()),
+		quorums:    quorums,
+		batchIndex: e.nonce,
+	}
+	e.batches[string(batchHeaderHash[:])] = string(requestID)
+	return &disperser.DisperseBlobReply{
+		Result:    disperser.BlobStatus_PROCESSING,
+		RequestId: requestID,
+	}, nil
+}
+
+func (e *EigenDA) DisperseBlob(_ context.Context, request *disperser.DisperseBlobRequest) (*disperser.DisperseBlobReply, error) {
+	return e.disperse(request)
+}
+
+func (e *EigenDA) DisperseBlobAuthenticated(stream disperser.Disperser_DisperseBlobAuthenticatedServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	request := first.GetDisperseRequest()
+	if request == nil {
+		return status.Error(codes.InvalidArgument, "expected a disperse request")
+	}
+	e.lock.Lock()
+	challenge := e.nonce
+	e.lock.Unlock()
+	if err := stream.Send(&disperser.AuthenticatedReply{
+		Payload: &disperser.AuthenticatedReply_BlobAuthHeader{
+			BlobAuthHeader: &disperser.BlobAuthHeader{ChallengeParameter: challenge},
+		},
+	}); err != nil {
+		return err
+	}
+
+	second, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if len(second.GetAuthenticationData().GetAuthenticationData()) == 0 {
+		return status.Error(codes.Unauthenticated, ErrEigenDAUnauthenticated.Error())
+	}
+	reply, err := e.disperse(request)
+	if err != nil {
+		return err
+	}
+	return stream.Send(&disperser.AuthenticatedReply{
+		Payload: &disperser.AuthenticatedReply_DisperseReply{DisperseReply: reply},
+	})
+}
+
+func (e *EigenDA) GetBlobStatus(_ context.Context, request *disperser.BlobStatusRequest) (*disperser.BlobStatusReply, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	blob, ok := e.blobs[string(request.GetRequestId())]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "no blob with this request ID")
+	}
+	blob.polls++
+	switch {
+	case blob.polls == 1:
+		return &disperser.BlobStatusReply{Status: disperser.BlobStatus_PROCESSING}, nil
+	case blob.polls == 2:
+		return &disperser.BlobStatusReply{Status: disperser.BlobStatus_CONFIRMED, Info: blob.info(request.GetRequestId())}, nil
+	default:
+		return &disperser.BlobStatusReply{Status: disperser.BlobStatus_FINALIZED, Info: blob.info(request.GetRequestId())}, nil
+	}
+}
+
+func (b *eigenDABlob) info(requestID []byte) *disperser.BlobInfo {
+	commitment := sha256.Sum256(b.data)
+	batchHeaderHash := sha256.Sum256(requestID)
+	batchRoot := sha256.Sum256(batchHeaderHash[:])
+
+	params := make([]*disperser.BlobQuorumParam, len(b.quorums))
+	quorumNumbers := make([]byte, len(b.quorums))
+	signedPercentages := make([]byte, len(b.quorums))
+	quorumIndexes := make([]byte, len(b.quorums))
+	for i, quorum := range b.quorums {
+		params[i] = &disperser.BlobQuorumParam{
+			QuorumNumber:                    quorum,
+			AdversaryThresholdPercentage:    EigenDAAdversaryThreshold,
+			ConfirmationThresholdPercentage: EigenDAConfirmationThreshold,
+			ChunkLength:                     1,
+		}
+		quorumNumbers[i] = byte(quorum)
+		signedPercentages[i] = 100
+		quorumIndexes[i] = byte(i)
+	}
+	return &disperser.BlobInfo{
+		BlobHeader: &disperser.BlobHeader{
+			Commitment:       &common.G1Commitment{X: commitment[:16], Y: commitment[16:]},
+			DataLength:       uint32((len(b.data) + 31) / 32),
+			BlobQuorumParams: params,
+		},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchId:   b.batchIndex,
+			BlobIndex: 0,
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader: &disperser.BatchHeader{
+					BatchRoot:               batchRoot[:],
+					QuorumNumbers:           quorumNumbers,
+					QuorumSignedPercentages: signedPercentages,
+					ReferenceBlockNumber:    b.batchIndex,
+				},
+				ConfirmationBlockNumber: b.batchIndex + 1,
+				BatchHeaderHash:         batchHeaderHash[:],
+			},
+			InclusionProof: []byte{},
+			QuorumIndexes:  quorumIndexes,
+		},
+	}
+}
+
+// find returns the blob of the batch with [batchHeaderHash]. Every batch
+// holds a single blob.
+func (e *EigenDA) find(batchHeaderHash []byte, blobIndex uint32) ([]byte, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	requestID, ok := e.batches[string(batchHeaderHash)]
+	if !ok || blobIndex != 0 {
+		return nil, status.Error(codes.NotFound, "no blob in this batch")
+	}
+	blob := e.blobs[requestID]
+	if blob.polls < 2 {
+		return nil, status.Error(codes.NotFound, "blob is not confirmed")
+	}
+	// Blobs are stored as whole field elements.
+	data := slices.Clone(blob.data)
+	if rem := len(data) % 32; rem != 0 {
+		data = append(data, make([]byte, 32-rem)...)
+	}
+	return data, nil
+}
+
+func (e *EigenDA) RetrieveBlob(_ context.Context, request *disperser.RetrieveBlobRequest) (*disperser.RetrieveBlobReply, error) {
+	data, err := e.find(request.GetBatchHeaderHash(), request.GetBlobIndex())
+	if err != nil {
+		return nil, err
+	}
+	return &disperser.RetrieveBlobReply{Data: data}, nil
+}
+
+// retrieverServer serves the retriever service of [EigenDA]. Its RetrieveBlob
+// method has the same name as the one of the disperser service.
+type retrieverServer struct {
+	retriever.UnimplementedRetrieverServer
+
+	e *EigenDA
+}
+
+func (r *retrieverServer) RetrieveBlob(_ context.Context, request *retriever.BlobRequest) (*retriever.BlobReply, error) {
+	data, err := r.e.find(request.GetBatchHeaderHash(), request.GetBlobIndex())
+	if err != nil {
+		return nil, err
+	}
+	return &retriever.BlobReply{Data: data}, nil
+}
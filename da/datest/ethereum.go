@@ -0,0 +1,407 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package datest provides in-process fakes of the DA layers so that the
+// adapters in [actions] and [da] can be tested without network access.
+package datest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	ErrNonceTooLow            = errors.New("nonce too low")
+	ErrNonceGap               = errors.New("nonce too high")
+	ErrUnderpriced            = errors.New("transaction underpriced")
+	ErrReplacementUnderpriced = errors.New("replacement transaction underpriced")
+	ErrInvalidSidecar         = errors.New("invalid blob sidecar")
+)
+
+const (
+	EthereumChainID = 1337
+	// EthereumBaseFee is the base fee of every block, in wei. The blob base
+	// fee is always the minimum of 1 wei.
+	EthereumBaseFee = params.GWei
+	// EthereumFinalityDepth is how far below the head the finalized block
+	// is.
+	EthereumFinalityDepth = 2
+)
+
+type ethereumTx struct {
+	tx          *types.Transaction
+	from        common.Address
+	blockHash   common.Hash
+	blockNumber uint64
+	index       uint
+	receipt     *types.Receipt
+}
+
+// Ethereum is a fake execution client. It serves the JSON-RPC methods used
+// by [actions.SendBlobAction], accepts blob transactions after checking
+// their sidecars and includes pending transactions in a block built every
+// block time. There is no state: balances are not checked and the roots of
+// the headers are those of empty blocks.
+type Ethereum struct {
+	// URL is the HTTP JSON-RPC endpoint.
+	URL string
+	// PrivateKey is a hex encoded key that can be used to send transactions.
+	PrivateKey string
+
+	server *httptest.Server
+	signer types.Signer
+
+	lock    sync.Mutex
+	headers []*types.Header
+	// nonces holds the next nonce to be included for every sender.
+	nonces  map[common.Address]uint64
+	pending map[common.Address]map[uint64]*types.Transaction
+	txs     map[common.Hash]*ethereumTx
+	blobs   map[common.Hash]*kzg4844.Blob
+}
+
+// NewEthereum starts a fake Ethereum node building a block every
+// [blockTime]. It is stopped when [t] completes.
+func NewEthereum(t testing.TB, blockTime time.Duration) *Ethereum {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &Ethereum{
+		PrivateKey: hex.EncodeToString(crypto.FromECDSA(key)),
+		signer:     types.NewCancunSigner(big.NewInt(EthereumChainID)),
+		headers:    []*types.Header{newEthereumHeader(nil, nil)},
+		nonces:     make(map[common.Address]uint64),
+		pending:    make(map[common.Address]map[uint64]*types.Transaction),
+		txs:        make(map[common.Hash]*ethereumTx),
+		blobs:      make(map[common.Hash]*kzg4844.Blob),
+	}
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &ethereumAPI{e: e}); err != nil {
+		t.Fatal(err)
+	}
+	e.server = httptest.NewServer(server)
+	e.URL = e.server.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(blockTime)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				e.BuildBlock()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		e.server.Close()
+		server.Stop()
+	})
+	return e
+}
+
+// BuildBlock includes the pending transactions that can be executed in a new
+// block, up to the blob limit of a block.
+func (e *Ethereum) BuildBlock() *types.Header {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	var (
+		included []*ethereumTx
+		blobGas  uint64
+	)
+	for from, byNonce := range e.pending {
+		for {
+			tx, ok := byNonce[e.nonces[from]]
+			if !ok || blobGas+tx.BlobGas() > params.MaxBlobGasPerBlock {
+				break
+			}
+			delete(byNonce, tx.Nonce())
+			e.nonces[from]++
+			blobGas += tx.BlobGas()
+			included = append(included, &ethereumTx{tx: tx, from: from})
+		}
+	}
+
+	parent := e.head()
+	header := newEthereumHeader(parent, included)
+	hash := header.Hash()
+	var cumulativeGasUsed uint64
+	for i, itx := range included {
+		cumulativeGasUsed += params.TxGas
+		itx.blockHash = hash
+		itx.blockNumber = header.Number.Uint64()
+		itx.index = uint(i)
+		itx.receipt = &types.Receipt{
+			Type:              itx.tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: cumulativeGasUsed,
+			Logs:              []*types.Log{},
+			TxHash:            itx.tx.Hash(),
+			GasUsed:           params.TxGas,
+			EffectiveGasPrice: itx.tx.EffectiveGasTipValue(header.BaseFee),
+			BlobGasUsed:       itx.tx.BlobGas(),
+			BlockHash:         hash,
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+		}
+		itx.receipt.EffectiveGasPrice.Add(itx.receipt.EffectiveGasPrice, header.BaseFee)
+		if itx.tx.Type() == types.BlobTxType {
+			itx.receipt.BlobGasPrice = big.NewInt(params.BlobTxMinBlobGasprice)
+			sidecar := itx.tx.BlobTxSidecar()
+			for j, blobHash := range itx.tx.BlobHashes() {
+				e.blobs[blobHash] = &sidecar.Blobs[j]
+			}
+		}
+		e.txs[itx.tx.Hash()] = itx
+	}
+	e.headers = append(e.headers, header)
+	return header
+}
+
+// Blob returns the blob with [versionedHash] carried by an included
+// transaction, as a beacon node would serve it.
+func (e *Ethereum) Blob(versionedHash common.Hash) (*kzg4844.Blob, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	blob, ok := e.blobs[versionedHash]
+	return blob, ok
+}
+
+func newEthereumHeader(parent *types.Header, txs []*ethereumTx) *types.Header {
+	var (
+		number     = new(big.Int)
+		parentHash common.Hash
+		excess     uint64
+		blobGas    uint64
+		now        = uint64(time.Now().Unix())
+	)
+	for _, itx := range txs {
+		blobGas += itx.tx.BlobGas()
+	}
+	if parent != nil {
+		number.Add(parent.Number, common.Big1)
+		parentHash = parent.Hash()
+		now = max(now, parent.Time+1)
+	}
+	return &types.Header{
+		ParentHash:    parentHash,
+		UncleHash:     types.EmptyUncleHash,
+		Root:          types.EmptyRootHash,
+		TxHash:        types.EmptyTxsHash,
+		ReceiptHash:   types.EmptyReceiptsHash,
+		Difficulty:    new(big.Int),
+		Number:        number,
+		GasLimit:      params.GenesisGasLimit,
+		GasUsed:       uint64(len(txs)) * params.TxGas,
+		Time:          now,
+		BaseFee:       big.NewInt(EthereumBaseFee),
+		ExcessBlobGas: &excess,
+		BlobGasUsed:   &blobGas,
+	}
+}
+
+func (e *Ethereum) head() *types.Header {
+	return e.headers[len(e.headers)-1]
+}
+
+func (e *Ethereum) pendingNonce(from common.Address) uint64 {
+	nonce := e.nonces[from]
+	for {
+		if _, ok := e.pending[from][nonce]; !ok {
+			return nonce
+		}
+		nonce++
+	}
+}
+
+func (e *Ethereum) sendTransaction(tx *types.Transaction) error {
+	from, err := types.Sender(e.signer, tx)
+	if err != nil {
+		return err
+	}
+	if tx.GasFeeCap().Cmp(big.NewInt(EthereumBaseFee)) < 0 {
+		return fmt.Errorf("%w: fee cap %s below base fee", ErrUnderpriced, tx.GasFeeCap())
+	}
+	if tx.Type() == types.BlobTxType {
+		if tx.BlobGasFeeCap().Cmp(big.NewInt(params.BlobTxMinBlobGasprice)) < 0 {
+			return fmt.Errorf("%w: blob fee cap %s below blob base fee", ErrUnderpriced, tx.BlobGasFeeCap())
+		}
+		if err := verifySidecar(tx); err != nil {
+			return err
+		}
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	switch nonce := tx.Nonce(); {
+	case nonce < e.nonces[from]:
+		return ErrNonceTooLow
+	case nonce > e.pendingNonce(from):
+		return ErrNonceGap
+	}
+	byNonce, ok := e.pending[from]
+	if !ok {
+		byNonce = make(map[uint64]*types.Transaction)
+		e.pending[from] = byNonce
+	}
+	if old, ok := byNonce[tx.Nonce()]; ok && !replaces(tx, old) {
+		return ErrReplacementUnderpriced
+	}
+	byNonce[tx.Nonce()] = tx
+	return nil
+}
+
+// replaces reports whether [tx] pays more than [old] in every fee.
+func replaces(tx *types.Transaction, old *types.Transaction) bool {
+	return tx.GasTipCap().Cmp(old.GasTipCap()) > 0 &&
+		tx.GasFeeCap().Cmp(old.GasFeeCap()) > 0 &&
+		(tx.Type() != types.BlobTxType || tx.BlobGasFeeCap().Cmp(old.BlobGasFeeCap()) > 0)
+}
+
+// verifySidecar checks that the sidecar of [tx] matches its versioned hashes
+// and that the blob proofs are valid.
+func verifySidecar(tx *types.Transaction) error {
+	sidecar := tx.BlobTxSidecar()
+	hashes := tx.BlobHashes()
+	if sidecar == nil || len(hashes) == 0 {
+		return fmt.Errorf("%w: missing", ErrInvalidSidecar)
+	}
+	if len(sidecar.Blobs) != len(hashes) || len(sidecar.Commitments) != len(hashes) || len(sidecar.Proofs) != len(hashes) {
+		return fmt.Errorf("%w: %d blobs for %d hashes", ErrInvalidSidecar, len(sidecar.Blobs), len(hashes))
+	}
+	for i, hash := range hashes {
+		if kzg4844.CalcBlobHashV1(sha256.New(), &sidecar.Commitments[i]) != hash {
+			return fmt.Errorf("%w: commitment %d does not match its hash", ErrInvalidSidecar, i)
+		}
+		if err := kzg4844.VerifyBlobProof(sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]); err != nil {
+			return fmt.Errorf("%w: blob %d: %w", ErrInvalidSidecar, i, err)
+		}
+	}
+	return nil
+}
+
+// ethereumAPI is the "eth" namespace of [Ethereum].
+type ethereumAPI struct {
+	e *Ethereum
+}
+
+func (*ethereumAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(EthereumChainID))
+}
+
+func (api *ethereumAPI) BlockNumber() hexutil.Uint64 {
+	api.e.lock.Lock()
+	defer api.e.lock.Unlock()
+
+	return hexutil.Uint64(api.e.head().Number.Uint64())
+}
+
+// Syncing reports that the node is in sync.
+func (*ethereumAPI) Syncing() bool {
+	return false
+}
+
+func (*ethereumAPI) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(params.GWei))
+}
+
+func (api *ethereumAPI) GetTransactionCount(address common.Address, block rpc.BlockNumberOrHash) hexutil.Uint64 {
+	api.e.lock.Lock()
+	defer api.e.lock.Unlock()
+
+	if number, ok := block.Number(); ok && number == rpc.PendingBlockNumber {
+		return hexutil.Uint64(api.e.pendingNonce(address))
+	}
+	return hexutil.Uint64(api.e.nonces[address])
+}
+
+func (api *ethereumAPI) GetBlockByNumber(number rpc.BlockNumber, _ bool) *types.Header {
+	api.e.lock.Lock()
+	defer api.e.lock.Unlock()
+
+	head := int64(len(api.e.headers) - 1)
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		return api.e.head()
+	case rpc.FinalizedBlockNumber, rpc.SafeBlockNumber:
+		return api.e.headers[max(head-EthereumFinalityDepth, 0)]
+	case rpc.EarliestBlockNumber:
+		return api.e.headers[0]
+	}
+	if number < 0 || int64(number) > head {
+		return nil
+	}
+	return api.e.headers[number]
+}
+
+func (api *ethereumAPI) SendRawTransaction(input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.e.sendTransaction(tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+func (api *ethereumAPI) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	api.e.lock.Lock()
+	defer api.e.lock.Unlock()
+
+	if itx, ok := api.e.txs[hash]; ok {
+		return itx.receipt
+	}
+	return nil
+}
+
+func (api *ethereumAPI) GetTransactionByHash(hash common.Hash) (map[string]any, error) {
+	api.e.lock.Lock()
+	defer api.e.lock.Unlock()
+
+	itx, ok := api.e.txs[hash]
+	if !ok {
+		return nil, nil
+	}
+	b, err := itx.tx.WithoutBlobTxSidecar().MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	fields["from"] = itx.from
+	fields["blockHash"] = itx.blockHash
+	fields["blockNumber"] = hexutil.Uint64(itx.blockNumber)
+	fields["transactionIndex"] = hexutil.Uint(itx.index)
+	return fields, nil
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// ReceiptIndex stores the receipts of posted payloads by layer and content
+// hash.
+type ReceiptIndex struct {
+	db database.Database
+}
+
+func NewReceiptIndex(db database.Database) *ReceiptIndex {
+	return &ReceiptIndex{db: db}
+}
+
+func receiptKey(layer Layer, digest ids.ID) []byte {
+	return append([]byte{byte(layer)}, digest[:]...)
+}
+
+// Get returns the receipt of the payload with [digest] on [layer], if it was
+// posted.
+func (i *ReceiptIndex) Get(layer Layer, digest ids.ID) (*BlobReceipt, bool, error) {
+	b, err := i.db.Get(receiptKey(layer, digest))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var receipt BlobReceipt
+	if err := json.Unmarshal(b, &receipt); err != nil {
+		return nil, false, err
+	}
+	return &receipt, true, nil
+}
+
+// Put records [receipt] as the receipt of the payload with [digest].
+func (i *ReceiptIndex) Put(digest ids.ID, receipt *BlobReceipt) error {
+	b, err := json.Marshal(receipt)
+	if err != nil {
+		return err
+	}
+	return i.db.Put(receiptKey(receipt.Layer, digest), b)
+}
+
+var _ DataAvailability = (*deduplicatedDA)(nil)
+
+type deduplicatedDA struct {
+	DataAvailability
+
+	index *ReceiptIndex
+}
+
+// NewDeduplicated returns a backend that posts a payload to [backend] only
+// if it was not posted before. Otherwise the receipt recorded in [index] is
+// returned again, unless the blob failed on the DA layer.
+func NewDeduplicated(backend DataAvailability, index *ReceiptIndex) DataAvailability {
+	return &deduplicatedDA{
+		DataAvailability: backend,
+		index:            index,
+	}
+}
+
+func (d *deduplicatedDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	digest := ids.ID(sha256.Sum256(data))
+	receipt, ok, err := d.index.Get(d.Layer(), digest)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		status, err := d.GetStatus(ctx, receipt)
+		if err != nil {
+			return nil, err
+		}
+		if status != StatusFailed {
+			receipt.Status = status
+			return receipt, nil
+		}
+	}
+
+	receipt, err = d.DataAvailability.Submit(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.index.Put(digest, receipt); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/stretchr/testify/require"
+)
+
+// statusBackend is a [memoryBackend] reporting [status] for every blob.
+type statusBackend struct {
+	*memoryBackend
+
+	status Status
+}
+
+func (s *statusBackend) GetStatus(context.Context, *BlobReceipt) (Status, error) {
+	return s.status, nil
+}
+
+func TestDeduplicatedSubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	celestia := &statusBackend{memoryBackend: &memoryBackend{layer: Celestia}, status: StatusFinalized}
+	index := NewReceiptIndex(memdb.New())
+	backend := NewDeduplicated(NewCompressed(celestia, CompressionZstd), index)
+
+	data := []byte("rollup batch")
+	first, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	second, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(first, second)
+	require.Equal(1, celestia.attempts)
+
+	_, err = backend.Submit(ctx, []byte("other batch"))
+	require.NoError(err)
+	require.Equal(2, celestia.attempts)
+
+	// The same payload is posted again to another layer.
+	eigenDA := &memoryBackend{layer: EigenDA}
+	_, err = NewDeduplicated(eigenDA, index).Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(1, eigenDA.attempts)
+
+	// And again to the same layer once the first blob failed.
+	celestia.status = StatusFailed
+	third, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(3, celestia.attempts)
+	require.Equal(StatusFinalized, third.Status)
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+var (
+	_ DataAvailability = (*eigenDA)(nil)
+	_ CostEstimator    = (*eigenDA)(nil)
+)
+
+type eigenDA struct {
+	action interface {
+		Disperse(ctx context.Context, data []byte) ([]byte, error)
+		actions.EigenDAStatusFetcher
+	}
+	retriever actions.EigenDARetrieverConfig
+	pricing   actions.EigenDAPricing
+}
+
+// NewEigenDA returns a backend that disperses blobs through the disperser
+// of [config] and reads them back through its retriever.
+func NewEigenDA(config EigenDAConfig) (DataAvailability, error) {
+	action, err := actions.NewSendEigenDAAction(config.AuthKey, config.Disperser)
+	if err != nil {
+		return nil, err
+	}
+	return &eigenDA{
+		action:    action,
+		retriever: config.Retriever,
+		pricing:   config.Pricing,
+	}, nil
+}
+
+func (*eigenDA) Layer() Layer {
+	return EigenDA
+}
+
+// Submit sends [data] to the disperser and returns once the request is
+// accepted. The receipt holds the request ID, the certificate is recorded by
+// [GetStatus] once the blob is confirmed.
+func (e *eigenDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	requestID, err := e.action.Disperse(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	receipt := newReceipt(EigenDA, data)
+	receipt.RequestID = requestID
+	return receipt, nil
+}
+
+// GetStatus polls the disperser for the request of [receipt]. Once the blob
+// is confirmed, its certificate and commitment are recorded in [receipt].
+func (e *eigenDA) GetStatus(ctx context.Context, receipt *BlobReceipt) (Status, error) {
+	if receipt.Layer != EigenDA {
+		return StatusUnknown, ErrLayerMismatch
+	}
+	if receipt.Status == StatusFinalized && receipt.Certificate != nil {
+		return StatusFinalized, nil
+	}
+	if len(receipt.RequestID) == 0 {
+		return StatusUnknown, ErrInvalidReceipt
+	}
+	update, err := e.action.Status(ctx, receipt.RequestID)
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if update.Certificate != nil {
+		receipt.Certificate = update.Certificate
+		receipt.Commitment = update.Certificate.Commitment
+	}
+	switch update.Status {
+	case actions.EigenDAStatusProcessing:
+		return StatusProcessing, nil
+	case actions.EigenDAStatusConfirmed:
+		return StatusConfirmed, nil
+	case actions.EigenDAStatusFinalized:
+		return StatusFinalized, nil
+	case actions.EigenDAStatusFailed:
+		return StatusFailed, nil
+	default:
+		return StatusUnknown, nil
+	}
+}
+
+// Retrieve reads the blob of the certificate in [receipt] back from the
+// retriever.
+func (e *eigenDA) Retrieve(ctx context.Context, receipt *BlobReceipt) ([]byte, error) {
+	if receipt.Layer != EigenDA {
+		return nil, ErrLayerMismatch
+	}
+	if receipt.Certificate == nil {
+		return nil, ErrInvalidReceipt
+	}
+	retriever, err := actions.NewEigenDARetriever(e.retriever)
+	if err != nil {
+		return nil, err
+	}
+	defer retriever.Close()
+
+	data, err := retriever.RetrieveBlob(ctx, receipt.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	// Blobs are padded to whole field elements.
+	if uint64(len(data)) > receipt.Size {
+		data = data[:receipt.Size]
+	}
+	return data, nil
+}
+
+func (e *eigenDA) Verify(ctx context.Context, receipt *BlobReceipt) error {
+	status, err := e.GetStatus(ctx, receipt)
+	if err != nil {
+		return err
+	}
+	if status != StatusFinalized {
+		return ErrNotIncluded
+	}
+	data, err := e.Retrieve(ctx, receipt)
+	if err != nil {
+		return err
+	}
+	return checkDigest(receipt, data)
+}
+
+// EstimateCost prices an on-demand dispersal, see [actions.EigenDAPricing].
+func (e *eigenDA) EstimateCost(_ context.Context, size uint64) (*big.Int, error) {
+	return e.pricing.Fee(int(size)), nil
+}
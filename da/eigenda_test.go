@@ -0,0 +1,58 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+)
+
+func TestEigenDASubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	key, err := crypto.GenerateKey()
+	require.NoError(err)
+	fake := datest.NewEigenDA(t)
+	backend, err := NewEigenDA(EigenDAConfig{
+		AuthKey:   hex.EncodeToString(crypto.FromECDSA(key)),
+		Disperser: fake.DisperserConfig(),
+		Retriever: fake.RetrieverConfig(),
+	})
+	require.NoError(err)
+
+	data := bytes.Repeat([]byte("rollup batch "), 100)
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(StatusProcessing, receipt.Status)
+	require.NotEmpty(receipt.RequestID)
+	require.Nil(receipt.Certificate)
+
+	// The fake disperser advances the blob one status every poll.
+	for _, expected := range []Status{StatusProcessing, StatusConfirmed, StatusFinalized} {
+		status, err := backend.GetStatus(ctx, receipt)
+		require.NoError(err)
+		require.Equal(expected, status)
+		receipt.Status = status
+	}
+	require.NotNil(receipt.Certificate)
+	require.Equal([]byte(receipt.Commitment), receipt.Certificate.Commitment)
+	require.Equal([]uint8{0, 1}, receipt.Certificate.QuorumIDs())
+
+	retrieved, err := backend.Retrieve(ctx, receipt)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+	require.NoError(backend.Verify(ctx, receipt))
+
+	receipt.Certificate.BatchHeaderHash = []byte("unknown batch")
+	_, err = backend.Retrieve(ctx, receipt)
+	require.Error(err)
+}
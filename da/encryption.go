@@ -0,0 +1,409 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+// Envelope layout
+// [encryptionMagic] + [version] + [scope kind] + [scope ID length] + [scope ID]
+//   + [key nonce] + [wrapped data key] + [data nonce] + [ciphertext]
+//
+// Every blob is encrypted with its own random data key, which is wrapped with
+// the key of its [KeyScope]. Everything before the ciphertext is
+// authenticated.
+
+const (
+	// KeySize is the size of master, scope and data keys.
+	KeySize = 32
+
+	encryptionMagic   = 0xDE
+	encryptionVersion = 1
+	wrappedKeySize    = KeySize + 16
+	maxScopeIDSize    = 255
+
+	scopeKeyInfo = "hypersdk-starter-kit/da/scope-key"
+	grantKeyInfo = "hypersdk-starter-kit/da/grant-key"
+)
+
+// KeyScopeKind is what a [KeyScope] derives its key from.
+type KeyScopeKind uint8
+
+const (
+	KeyScopeNamespace KeyScopeKind = iota
+	KeyScopeAddress
+)
+
+var keyScopeNames = []string{"namespace", "address"}
+
+func (k KeyScopeKind) String() string {
+	if int(k) < len(keyScopeNames) {
+		return keyScopeNames[k]
+	}
+	return fmt.Sprintf("scope(%d)", uint8(k))
+}
+
+func (k KeyScopeKind) MarshalText() ([]byte, error) {
+	if int(k) >= len(keyScopeNames) {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownKeyScope, k)
+	}
+	return []byte(k.String()), nil
+}
+
+func (k *KeyScopeKind) UnmarshalText(text []byte) error {
+	for i, name := range keyScopeNames {
+		if name == string(text) {
+			*k = KeyScopeKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownKeyScope, text)
+}
+
+// KeyScope selects the key a blob is encrypted with. Readers are granted
+// access per scope, see [Keyring.Grant].
+type KeyScope struct {
+	Kind KeyScopeKind `json:"kind"`
+	// ID is the namespace or the address.
+	ID codec.Bytes `json:"id"`
+}
+
+// NamespaceScope returns the scope of the blobs posted under [namespace].
+func NamespaceScope(namespace []byte) KeyScope {
+	return KeyScope{Kind: KeyScopeNamespace, ID: namespace}
+}
+
+// AddressScope returns the scope of the blobs posted by [addr].
+func AddressScope(addr codec.Address) KeyScope {
+	return KeyScope{Kind: KeyScopeAddress, ID: addr[:]}
+}
+
+func (s KeyScope) bytes() []byte {
+	return append([]byte{byte(s.Kind), byte(len(s.ID))}, s.ID...)
+}
+
+func (s KeyScope) verify() error {
+	if int(s.Kind) >= len(keyScopeNames) {
+		return fmt.Errorf("%w: %d", ErrUnknownKeyScope, s.Kind)
+	}
+	if len(s.ID) == 0 || len(s.ID) > maxScopeIDSize {
+		return fmt.Errorf("%w: ID of %d bytes", ErrUnknownKeyScope, len(s.ID))
+	}
+	return nil
+}
+
+// Keyring holds the keys blobs are encrypted with. A keyring created with a
+// master key derives the key of any scope. Readers hold the keys of the
+// scopes they were granted instead.
+type Keyring struct {
+	master []byte
+
+	lock sync.RWMutex
+	keys map[string][]byte
+}
+
+// NewKeyring returns a keyring deriving scope keys from [master], which may
+// be nil for a keyring holding only granted keys.
+func NewKeyring(master []byte) (*Keyring, error) {
+	if master != nil && len(master) != KeySize {
+		return nil, fmt.Errorf("%w: %d bytes", ErrInvalidKeySize, len(master))
+	}
+	return &Keyring{
+		master: master,
+		keys:   make(map[string][]byte),
+	}, nil
+}
+
+// Key returns the key of [scope].
+func (k *Keyring) Key(scope KeyScope) ([]byte, error) {
+	if err := scope.verify(); err != nil {
+		return nil, err
+	}
+	id := string(scope.bytes())
+	k.lock.RLock()
+	key, ok := k.keys[id]
+	k.lock.RUnlock()
+	if ok {
+		return key, nil
+	}
+	if k.master == nil {
+		return nil, fmt.Errorf("%w: %s %x", ErrKeyNotFound, scope.Kind, scope.ID)
+	}
+
+	key = make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, k.master, nil, append([]byte(scopeKeyInfo), id...)), key); err != nil {
+		return nil, err
+	}
+	k.lock.Lock()
+	k.keys[id] = key
+	k.lock.Unlock()
+	return key, nil
+}
+
+// KeyGrant gives access to the blobs of a single scope. It must be kept
+// secret, use [SealGrant] to send it to a reader.
+type KeyGrant struct {
+	Scope KeyScope    `json:"scope"`
+	Key   codec.Bytes `json:"key"`
+}
+
+// Grant returns a grant for [scope].
+func (k *Keyring) Grant(scope KeyScope) (*KeyGrant, error) {
+	key, err := k.Key(scope)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyGrant{Scope: scope, Key: key}, nil
+}
+
+// Import adds the key of [grant] to the keyring.
+func (k *Keyring) Import(grant *KeyGrant) error {
+	if err := grant.Scope.verify(); err != nil {
+		return err
+	}
+	if len(grant.Key) != KeySize {
+		return fmt.Errorf("%w: %d bytes", ErrInvalidKeySize, len(grant.Key))
+	}
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	k.keys[string(grant.Scope.bytes())] = grant.Key
+	return nil
+}
+
+// SealedGrant is a [KeyGrant] encrypted to the X25519 key of a reader. Its
+// scope is left in the clear.
+type SealedGrant struct {
+	Scope        KeyScope    `json:"scope"`
+	EphemeralKey codec.Bytes `json:"ephemeral_key"`
+	Nonce        codec.Bytes `json:"nonce"`
+	Ciphertext   codec.Bytes `json:"ciphertext"`
+}
+
+// SealGrant encrypts [grant] so only the holder of the private key of
+// [recipient] can open it.
+func SealGrant(grant *KeyGrant, recipient *ecdh.PublicKey) (*SealedGrant, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := grantAEAD(ephemeral, recipient, ephemeral.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomBytes(aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	return &SealedGrant{
+		Scope:        grant.Scope,
+		EphemeralKey: ephemeral.PublicKey().Bytes(),
+		Nonce:        nonce,
+		Ciphertext:   aead.Seal(nil, nonce, grant.Key, grant.Scope.bytes()),
+	}, nil
+}
+
+// Open decrypts the grant with the private key it was sealed to.
+func (s *SealedGrant) Open(priv *ecdh.PrivateKey) (*KeyGrant, error) {
+	ephemeral, err := ecdh.X25519().NewPublicKey(s.EphemeralKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := grantAEAD(priv, ephemeral, ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, ErrInvalidEnvelope
+	}
+	key, err := aead.Open(nil, s.Nonce, s.Ciphertext, s.Scope.bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvelope, err)
+	}
+	return &KeyGrant{Scope: s.Scope, Key: key}, nil
+}
+
+// grantAEAD derives the key sealing a grant from the X25519 exchange of
+// [priv] and [pub], bound to the [ephemeral] key of the sender.
+func grantAEAD(priv *ecdh.PrivateKey, pub *ecdh.PublicKey, ephemeral *ecdh.PublicKey) (cipher.AEAD, error) {
+	secret, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, ephemeral.Bytes(), []byte(grantKeyInfo)), key); err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Encrypt seals [data] in an envelope readable by the holders of the key of
+// [scope].
+func Encrypt(keyring *Keyring, scope KeyScope, data []byte) ([]byte, error) {
+	scopeKey, err := keyring.Key(scope)
+	if err != nil {
+		return nil, err
+	}
+	keyAEAD, err := newAEAD(scopeKey)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := randomBytes(KeySize)
+	if err != nil {
+		return nil, err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	keyNonce, err := randomBytes(keyAEAD.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	dataNonce, err := randomBytes(dataAEAD.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+
+	header := append([]byte{encryptionMagic, encryptionVersion}, scope.bytes()...)
+	header = append(header, keyNonce...)
+	header = keyAEAD.Seal(header, keyNonce, dataKey, header)
+	header = append(header, dataNonce...)
+	return dataAEAD.Seal(header, dataNonce, data, header), nil
+}
+
+// EnvelopeOverhead returns how many bytes [Encrypt] adds to a payload
+// encrypted for [scope].
+func EnvelopeOverhead(scope KeyScope) int {
+	// Both AEADs are AES-GCM with standard nonce and tag sizes.
+	const nonceSize, tagSize = 12, 16
+	return len(scope.bytes()) + 2 + nonceSize + wrappedKeySize + nonceSize + tagSize
+}
+
+// EnvelopeScope returns the scope [envelope] was encrypted for.
+func EnvelopeScope(envelope []byte) (KeyScope, error) {
+	if len(envelope) < 4 || envelope[0] != encryptionMagic || envelope[1] != encryptionVersion {
+		return KeyScope{}, ErrInvalidEnvelope
+	}
+	size := int(envelope[3])
+	if len(envelope) < 4+size {
+		return KeyScope{}, ErrInvalidEnvelope
+	}
+	scope := KeyScope{
+		Kind: KeyScopeKind(envelope[2]),
+		ID:   codec.Bytes(envelope[4 : 4+size]),
+	}
+	return scope, scope.verify()
+}
+
+// Decrypt opens an envelope created by [Encrypt] with the key of its scope
+// in [keyring].
+func Decrypt(keyring *Keyring, envelope []byte) ([]byte, error) {
+	scope, err := EnvelopeScope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	scopeKey, err := keyring.Key(scope)
+	if err != nil {
+		return nil, err
+	}
+	keyAEAD, err := newAEAD(scopeKey)
+	if err != nil {
+		return nil, err
+	}
+	// Both AEADs are AES-GCM and share their nonce and tag sizes.
+	var (
+		nonceSize  = keyAEAD.NonceSize()
+		keyStart   = 4 + len(scope.ID) + nonceSize
+		dataStart  = keyStart + wrappedKeySize + nonceSize
+		headerSize = dataStart
+	)
+	if len(envelope) < headerSize+keyAEAD.Overhead() {
+		return nil, ErrInvalidEnvelope
+	}
+	dataKey, err := keyAEAD.Open(nil, envelope[keyStart-nonceSize:keyStart], envelope[keyStart:keyStart+wrappedKeySize], envelope[:keyStart])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvelope, err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := dataAEAD.Open(nil, envelope[dataStart-nonceSize:dataStart], envelope[headerSize:], envelope[:headerSize])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvelope, err)
+	}
+	return data, nil
+}
+
+var _ DataAvailability = (*encryptedDA)(nil)
+
+type encryptedDA struct {
+	DataAvailability
+
+	keyring *Keyring
+	scope   KeyScope
+}
+
+// NewEncrypted returns a backend encrypting payloads for [scope] before
+// posting them to [backend], and decrypting them on retrieval with the keys
+// of [keyring]. Compression must wrap it, encrypted payloads do not compress.
+func NewEncrypted(backend DataAvailability, keyring *Keyring, scope KeyScope) DataAvailability {
+	return &encryptedDA{
+		DataAvailability: backend,
+		keyring:          keyring,
+		scope:            scope,
+	}
+}
+
+func (e *encryptedDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	envelope, err := Encrypt(e.keyring, e.scope, data)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := e.DataAvailability.Submit(ctx, envelope)
+	if err != nil {
+		return nil, err
+	}
+	scope := e.scope
+	setPayload(receipt, data).Encryption = &scope
+	return receipt, nil
+}
+
+func (e *encryptedDA) Retrieve(ctx context.Context, receipt *BlobReceipt) ([]byte, error) {
+	envelope, err := e.DataAvailability.Retrieve(ctx, receipt)
+	if err != nil {
+		return nil, err
+	}
+	return Decrypt(e.keyring, envelope)
+}
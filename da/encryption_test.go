@@ -0,0 +1,154 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func newTestKeyring(t *testing.T) *Keyring {
+	master := make([]byte, KeySize)
+	_, err := rand.Read(master)
+	require.NoError(t, err)
+	keyring, err := NewKeyring(master)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	require := require.New(t)
+
+	keyring := newTestKeyring(t)
+	scope := AddressScope(codectest.NewRandomAddress())
+	data := []byte("rollup batch")
+
+	envelope, err := Encrypt(keyring, scope, data)
+	require.NoError(err)
+	require.NotContains(string(envelope), string(data))
+	require.Len(envelope, len(data)+EnvelopeOverhead(scope))
+	envelopeScope, err := EnvelopeScope(envelope)
+	require.NoError(err)
+	require.Equal(scope, envelopeScope)
+
+	decrypted, err := Decrypt(keyring, envelope)
+	require.NoError(err)
+	require.Equal(data, decrypted)
+
+	// Every envelope uses its own data key.
+	other, err := Encrypt(keyring, scope, data)
+	require.NoError(err)
+	require.NotEqual(envelope, other)
+
+	// Another master key derives other scope keys.
+	_, err = Decrypt(newTestKeyring(t), envelope)
+	require.ErrorIs(err, ErrInvalidEnvelope)
+
+	for _, i := range []int{0, 2, len(envelope) - 1} {
+		tampered := bytes.Clone(envelope)
+		tampered[i] ^= 0x01
+		_, err = Decrypt(keyring, tampered)
+		require.Error(err)
+	}
+	_, err = Decrypt(keyring, envelope[:len(envelope)-len(data)-1])
+	require.ErrorIs(err, ErrInvalidEnvelope)
+}
+
+func TestKeyGrant(t *testing.T) {
+	require := require.New(t)
+
+	keyring := newTestKeyring(t)
+	granted := NamespaceScope([]byte("tenant-a"))
+	other := NamespaceScope([]byte("tenant-b"))
+	envelope, err := Encrypt(keyring, granted, []byte("rollup batch"))
+	require.NoError(err)
+	otherEnvelope, err := Encrypt(keyring, other, []byte("rollup batch"))
+	require.NoError(err)
+
+	readerKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(err)
+	grant, err := keyring.Grant(granted)
+	require.NoError(err)
+	sealed, err := SealGrant(grant, readerKey.PublicKey())
+	require.NoError(err)
+
+	// The sealed grant is what is handed to the reader.
+	b, err := json.Marshal(sealed)
+	require.NoError(err)
+	var received SealedGrant
+	require.NoError(json.Unmarshal(b, &received))
+	require.Equal(granted, received.Scope)
+
+	wrongKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	require.NoError(err)
+	_, err = received.Open(wrongKey)
+	require.ErrorIs(err, ErrInvalidEnvelope)
+
+	opened, err := received.Open(readerKey)
+	require.NoError(err)
+	require.Equal(grant, opened)
+
+	reader, err := NewKeyring(nil)
+	require.NoError(err)
+	require.NoError(reader.Import(opened))
+	decrypted, err := Decrypt(reader, envelope)
+	require.NoError(err)
+	require.Equal([]byte("rollup batch"), decrypted)
+	_, err = Decrypt(reader, otherEnvelope)
+	require.ErrorIs(err, ErrKeyNotFound)
+}
+
+func TestNewKeyring(t *testing.T) {
+	require := require.New(t)
+
+	_, err := NewKeyring(make([]byte, KeySize-1))
+	require.ErrorIs(err, ErrInvalidKeySize)
+
+	keyring := newTestKeyring(t)
+	_, err = keyring.Key(KeyScope{Kind: KeyScopeAddress + 1, ID: []byte{0x01}})
+	require.ErrorIs(err, ErrUnknownKeyScope)
+	_, err = keyring.Key(NamespaceScope(nil))
+	require.ErrorIs(err, ErrUnknownKeyScope)
+	require.ErrorIs(keyring.Import(&KeyGrant{Scope: NamespaceScope([]byte{0x01})}), ErrInvalidKeySize)
+}
+
+func TestEncryptedSubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewCelestia(t)
+	celestia := NewCelestia(fake.URL, "token", nil, 0)
+	keyring := newTestKeyring(t)
+	scope := NamespaceScope([]byte("tenant-a"))
+	backend := NewCompressed(NewEncrypted(celestia, keyring, scope), CompressionZstd)
+
+	data := bytes.Repeat([]byte("rollup batch "), 1000)
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(ids.ID(sha256.Sum256(data)), receipt.Encoding.Digest)
+	require.Equal(CompressionZstd, receipt.Encoding.Compression)
+	require.Equal(&scope, receipt.Encoding.Encryption)
+
+	// The DA layer only holds the envelope, whose digest is the receipt
+	// digest.
+	envelope, err := celestia.Retrieve(ctx, receipt)
+	require.NoError(err)
+	require.Equal(receipt.Digest, ids.ID(sha256.Sum256(envelope)))
+	require.Less(len(envelope), len(data))
+
+	retrieved, err := backend.Retrieve(ctx, receipt)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import "errors"
+
+var (
+	ErrUnknownLayer             = errors.New("unknown DA layer")
+	ErrLayerMismatch            = errors.New("receipt is for a different DA layer")
+	ErrDigestMismatch           = errors.New("retrieved data does not match receipt digest")
+	ErrRetrieveNotSupported     = errors.New("retrieval is not supported by this DA layer")
+	ErrInvalidReceipt           = errors.New("invalid receipt")
+	ErrNotIncluded              = errors.New("blob is not included")
+	ErrNoBackends               = errors.New("no DA backends")
+	ErrDuplicateLayer           = errors.New("DA layer is configured twice")
+	ErrInvalidQuorum            = errors.New("invalid quorum")
+	ErrQuorumNotMet             = errors.New("quorum of DA layers not met")
+	ErrMissingBackend           = errors.New("no backend for DA layer")
+	ErrNoLayerAvailable         = errors.New("no DA layer accepted the blob")
+	ErrLayerCoolingDown         = errors.New("DA layer failed recently")
+	ErrUnhealthy                = errors.New("DA layer is unhealthy")
+	ErrPriceTooHigh             = errors.New("DA layer price is above max price")
+	ErrSyncing                  = errors.New("DA node is syncing")
+	ErrCostNotSupported         = errors.New("cost estimation is not supported by this DA layer")
+	ErrUnknownCompression       = errors.New("unknown compression")
+	ErrInvalidCompressionHeader = errors.New("invalid compression header")
+	ErrPayloadTooLarge          = errors.New("payload is too large")
+	ErrUnknownKeyScope          = errors.New("unknown key scope")
+	ErrInvalidKeySize           = errors.New("invalid key size")
+	ErrKeyNotFound              = errors.New("no key for scope")
+	ErrInvalidEnvelope          = errors.New("invalid encryption envelope")
+	ErrEmptyPayload             = errors.New("payload is empty")
+	ErrInvalidShardingPolicy    = errors.New("invalid sharding policy")
+	ErrShardNotPosted           = errors.New("shard was not posted")
+	ErrInvalidManifest          = errors.New("invalid shard manifest")
+	ErrNotEnoughShards          = errors.New("not enough shards retrieved")
+	ErrInvalidLocator           = errors.New("invalid locator")
+)
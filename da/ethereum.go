@@ -0,0 +1,210 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+var (
+	_ DataAvailability = (*ethereumDA)(nil)
+	_ HealthChecker    = (*ethereumDA)(nil)
+	_ Pricer           = (*ethereumDA)(nil)
+	_ CostEstimator    = (*ethereumDA)(nil)
+)
+
+type ethereumDA struct {
+	client        *ethclient.Client
+	privateKey    string
+	fees          actions.BlobFeeConfig
+	confirmations uint64
+}
+
+// NewEthereum returns a backend that posts EIP-4844 blob transactions priced
+// with [fees]. Submit returns once the transactions have [confirmations]
+// blocks on top of them.
+func NewEthereum(
+	client *ethclient.Client,
+	privateKey string,
+	fees actions.BlobFeeConfig,
+	confirmations uint64,
+) DataAvailability {
+	return &ethereumDA{
+		client:        client,
+		privateKey:    privateKey,
+		fees:          fees,
+		confirmations: confirmations,
+	}
+}
+
+func (*ethereumDA) Layer() Layer {
+	return Ethereum
+}
+
+func (e *ethereumDA) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	action := &actions.SendBlobAction{
+		Client:        e.client,
+		PrivateKey:    e.privateKey,
+		Fees:          e.fees,
+		Confirmations: e.confirmations,
+	}
+	result, err := action.Execute(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	receipt := newReceipt(Ethereum, data)
+	receipt.Status = StatusConfirmed
+	receipt.Height = result.BlockNumber
+	receipt.TxHash = common.HexToHash(result.TransactionHash).Bytes()
+	for _, tx := range result.Transactions[1:] {
+		receipt.ExtraTxHashes = append(receipt.ExtraTxHashes, common.HexToHash(tx.TransactionHash).Bytes())
+	}
+	for _, tx := range result.Transactions {
+		for _, hash := range tx.BlobHashes {
+			receipt.BlobHashes = append(receipt.BlobHashes, hash.Bytes())
+		}
+	}
+	receipt.Commitment = blobCommitment(receipt.BlobHashes)
+	return receipt, nil
+}
+
+// GetStatus returns the least advanced status of the transactions carrying
+// [receipt].
+func (e *ethereumDA) GetStatus(ctx context.Context, receipt *BlobReceipt) (Status, error) {
+	if receipt.Layer != Ethereum {
+		return StatusUnknown, ErrLayerMismatch
+	}
+	status := StatusFinalized
+	for _, txHash := range txHashes(receipt) {
+		txStatus, err := e.txStatus(ctx, txHash)
+		if err != nil {
+			return StatusUnknown, err
+		}
+		if txStatus == StatusFailed {
+			return StatusFailed, nil
+		}
+		status = min(status, txStatus)
+	}
+	return status, nil
+}
+
+func (e *ethereumDA) txStatus(ctx context.Context, txHash common.Hash) (Status, error) {
+	txReceipt, err := e.client.TransactionReceipt(ctx, txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return StatusProcessing, nil
+	}
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if txReceipt.Status != types.ReceiptStatusSuccessful {
+		return StatusFailed, nil
+	}
+	finalized, err := e.client.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return StatusUnknown, err
+	}
+	if txReceipt.BlockNumber.Cmp(finalized.Number) <= 0 {
+		return StatusFinalized, nil
+	}
+	return StatusConfirmed, nil
+}
+
+// Retrieve is not supported: execution clients do not serve blob sidecars,
+// they have to be fetched from a beacon node before they are pruned.
+func (*ethereumDA) Retrieve(context.Context, *BlobReceipt) ([]byte, error) {
+	return nil, ErrRetrieveNotSupported
+}
+
+func (e *ethereumDA) Verify(ctx context.Context, receipt *BlobReceipt) error {
+	status, err := e.GetStatus(ctx, receipt)
+	if err != nil {
+		return err
+	}
+	if status != StatusConfirmed && status != StatusFinalized {
+		return ErrNotIncluded
+	}
+	var blobHashes []codec.Bytes
+	for _, txHash := range txHashes(receipt) {
+		tx, _, err := e.client.TransactionByHash(ctx, txHash)
+		if err != nil {
+			return err
+		}
+		if tx.Type() != types.BlobTxType || len(tx.BlobHashes()) == 0 {
+			return ErrNotIncluded
+		}
+		for _, hash := range tx.BlobHashes() {
+			blobHashes = append(blobHashes, hash.Bytes())
+		}
+	}
+	sameHashes := slices.EqualFunc(blobHashes, receipt.BlobHashes, func(a, b codec.Bytes) bool {
+		return bytes.Equal(a, b)
+	})
+	if !sameHashes || !bytes.Equal(blobCommitment(blobHashes), receipt.Commitment) {
+		return actions.ErrBlobHashMismatch
+	}
+	return nil
+}
+
+// HealthCheck fails if the client is not connected or still syncing.
+func (e *ethereumDA) HealthCheck(ctx context.Context) error {
+	progress, err := e.client.SyncProgress(ctx)
+	if err != nil {
+		return err
+	}
+	if progress != nil {
+		return ErrSyncing
+	}
+	return nil
+}
+
+// Price returns the blob base fee in wei per blob gas.
+func (e *ethereumDA) Price(ctx context.Context) (float64, error) {
+	fee, err := actions.BlobBaseFee(ctx, e.client)
+	if err != nil {
+		return 0, err
+	}
+	price, _ := new(big.Float).SetInt(fee).Float64()
+	return price, nil
+}
+
+func (e *ethereumDA) EstimateCost(ctx context.Context, size uint64) (*big.Int, error) {
+	return actions.EstimateBlobCost(ctx, e.client, int(size))
+}
+
+// blobCommitment binds a payload to the contents of its blobs rather than to
+// the transactions carrying them: it is the versioned hash of its only blob,
+// or the sha256 of the versioned hashes of its blobs in order.
+func blobCommitment(blobHashes []codec.Bytes) []byte {
+	if len(blobHashes) == 1 {
+		return blobHashes[0]
+	}
+	h := sha256.New()
+	for _, hash := range blobHashes {
+		h.Write(hash)
+	}
+	return h.Sum(nil)
+}
+
+func txHashes(receipt *BlobReceipt) []common.Hash {
+	hashes := make([]common.Hash, 0, 1+len(receipt.ExtraTxHashes))
+	hashes = append(hashes, common.BytesToHash(receipt.TxHash))
+	for _, txHash := range receipt.ExtraTxHashes {
+		hashes = append(hashes, common.BytesToHash(txHash))
+	}
+	return hashes
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+)
+
+func TestEthereumSubmit(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	fake := datest.NewEthereum(t, 20*time.Millisecond)
+	client, err := ethclient.Dial(fake.URL)
+	require.NoError(err)
+	t.Cleanup(client.Close)
+
+	fees := actions.NewDefaultBlobFeeConfig()
+	fees.PollInterval = 10 * time.Millisecond
+	backend := NewEthereum(client, fake.PrivateKey, fees, 1)
+
+	data := bytes.Repeat([]byte("rollup batch "), 20_000)
+	receipt, err := backend.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(StatusConfirmed, receipt.Status)
+	require.NotZero(receipt.Height)
+	require.NoError(backend.Verify(ctx, receipt))
+
+	require.Eventually(func() bool {
+		status, err := backend.GetStatus(ctx, receipt)
+		return err == nil && status == StatusFinalized
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// The payload is registered by the versioned hashes of its blobs.
+	tx, _, err := client.TransactionByHash(ctx, common.BytesToHash(receipt.TxHash))
+	require.NoError(err)
+	require.Len(receipt.BlobHashes, 3)
+	commitment := sha256.New()
+	for i, hash := range tx.BlobHashes() {
+		require.Equal(hash.Bytes(), []byte(receipt.BlobHashes[i]))
+		commitment.Write(hash.Bytes())
+	}
+	require.Equal(commitment.Sum(nil), []byte(receipt.Commitment))
+
+	tampered := *receipt
+	tampered.Commitment = receipt.BlobHashes[0]
+	require.ErrorIs(backend.Verify(ctx, &tampered), actions.ErrBlobHashMismatch)
+
+	// The payload can be rebuilt from the blobs a beacon node would serve.
+	blobs := make([]kzg4844.Blob, len(tx.BlobHashes()))
+	for i, hash := range tx.BlobHashes() {
+		blob, ok := fake.Blob(hash)
+		require.True(ok)
+		blobs[i] = *blob
+	}
+	decoded, err := actions.DecodeBlobs(blobs)
+	require.NoError(err)
+	require.Equal(data, decoded)
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthChecker is implemented by backends that can check their endpoint
+// before a submission.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// Pricer is implemented by backends whose price of posting data varies.
+type Pricer interface {
+	// Price returns the current unit price of posting data, in the unit of
+	// the layer: wei per blob gas on Ethereum and utia per gas on Celestia.
+	Price(ctx context.Context) (float64, error)
+}
+
+// FailoverLayer is one of the layers a [Failover] routes to.
+type FailoverLayer struct {
+	Layer Layer `json:"layer"`
+	// MaxPrice is the highest [Pricer] price the layer is used at, 0
+	// disables the check. It is ignored if the backend is not a [Pricer].
+	MaxPrice float64 `json:"max_price,omitempty"`
+}
+
+// FailoverPolicy configures how a [Failover] picks the layer a blob is
+// posted to.
+type FailoverPolicy struct {
+	// Layers are tried in order until one accepts the blob.
+	Layers []FailoverLayer `json:"layers"`
+	// HealthTimeout bounds the health check and price query of a layer.
+	HealthTimeout time.Duration `json:"health_timeout"`
+	// Cooldown is how long a layer is skipped after it failed a health check
+	// or a submission.
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+func NewDefaultFailoverPolicy() FailoverPolicy {
+	return FailoverPolicy{
+		HealthTimeout: 5 * time.Second,
+		Cooldown:      time.Minute,
+	}
+}
+
+type failoverBackend struct {
+	FailoverLayer
+	backend DataAvailability
+}
+
+// Failover posts every blob to the first layer of its preferences that is
+// healthy and under its max price, and moves to the next one if the
+// submission fails.
+type Failover struct {
+	layers        []failoverBackend
+	healthTimeout time.Duration
+	cooldown      time.Duration
+
+	lock      sync.Mutex
+	downUntil map[Layer]time.Time
+}
+
+// NewFailover returns a failover routing to [backends] in the order of
+// [policy.Layers]. Every layer of the policy must have a backend.
+func NewFailover(backends []DataAvailability, policy FailoverPolicy) (*Failover, error) {
+	if len(policy.Layers) == 0 {
+		return nil, ErrNoBackends
+	}
+	byLayer := make(map[Layer]DataAvailability, len(backends))
+	for _, backend := range backends {
+		byLayer[backend.Layer()] = backend
+	}
+	layers := make([]failoverBackend, len(policy.Layers))
+	seen := make(map[Layer]struct{}, len(policy.Layers))
+	for i, layer := range policy.Layers {
+		if _, ok := seen[layer.Layer]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateLayer, layer.Layer)
+		}
+		seen[layer.Layer] = struct{}{}
+		backend, ok := byLayer[layer.Layer]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingBackend, layer.Layer)
+		}
+		layers[i] = failoverBackend{FailoverLayer: layer, backend: backend}
+	}
+	return &Failover{
+		layers:        layers,
+		healthTimeout: policy.HealthTimeout,
+		cooldown:      policy.Cooldown,
+		downUntil:     make(map[Layer]time.Time),
+	}, nil
+}
+
+// Backend returns the backend posting to [layer].
+func (f *Failover) Backend(layer Layer) (DataAvailability, bool) {
+	for _, l := range f.layers {
+		if l.Layer == layer {
+			return l.backend, true
+		}
+	}
+	return nil, false
+}
+
+// FailoverAttempt records why a layer was not used.
+type FailoverAttempt struct {
+	Layer Layer `json:"layer"`
+	// Price is set if the layer was priced.
+	Price float64 `json:"price,omitempty"`
+	Error string  `json:"error"`
+
+	err error
+}
+
+// FailoverResult is the outcome of a [Failover] submission.
+type FailoverResult struct {
+	// Layer is the layer the blob was posted to.
+	Layer   Layer        `json:"layer"`
+	Receipt *BlobReceipt `json:"receipt"`
+	// Skipped lists the layers preferred over [Layer], in order.
+	Skipped []FailoverAttempt `json:"skipped,omitempty"`
+}
+
+// Submit posts [data] to the first usable layer. If none accepts it, the
+// returned error wraps [ErrNoLayerAvailable] and the reason every layer was
+// skipped.
+func (f *Failover) Submit(ctx context.Context, data []byte) (*FailoverResult, error) {
+	result := &FailoverResult{}
+	for _, layer := range f.layers {
+		price, err := f.check(ctx, layer)
+		if err == nil {
+			result.Receipt, err = layer.backend.Submit(ctx, data)
+			if err == nil {
+				result.Layer = layer.Layer
+				return result, nil
+			}
+			f.markDown(layer.Layer)
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		result.Skipped = append(result.Skipped, FailoverAttempt{
+			Layer: layer.Layer,
+			Price: price,
+			Error: err.Error(),
+			err:   err,
+		})
+	}
+
+	errs := make([]error, len(result.Skipped))
+	for i, attempt := range result.Skipped {
+		errs[i] = fmt.Errorf("%s: %w", attempt.Layer, attempt.err)
+	}
+	return result, fmt.Errorf("%w: %w", ErrNoLayerAvailable, errors.Join(errs...))
+}
+
+// check returns an error if [layer] should be skipped, along with its price
+// if it was queried.
+func (f *Failover) check(ctx context.Context, layer failoverBackend) (float64, error) {
+	if f.isDown(layer.Layer) {
+		return 0, ErrLayerCoolingDown
+	}
+	checkCtx := ctx
+	if f.healthTimeout > 0 {
+		var cancel context.CancelFunc
+		checkCtx, cancel = context.WithTimeout(ctx, f.healthTimeout)
+		defer cancel()
+	}
+	if checker, ok := layer.backend.(HealthChecker); ok {
+		if err := checker.HealthCheck(checkCtx); err != nil {
+			f.markDown(layer.Layer)
+			return 0, fmt.Errorf("%w: %w", ErrUnhealthy, err)
+		}
+	}
+	pricer, ok := layer.backend.(Pricer)
+	if !ok || layer.MaxPrice == 0 {
+		return 0, nil
+	}
+	price, err := pricer.Price(checkCtx)
+	if err != nil {
+		f.markDown(layer.Layer)
+		return 0, fmt.Errorf("%w: %w", ErrUnhealthy, err)
+	}
+	if price > layer.MaxPrice {
+		return price, fmt.Errorf("%w: %g > %g", ErrPriceTooHigh, price, layer.MaxPrice)
+	}
+	return price, nil
+}
+
+func (f *Failover) isDown(layer Layer) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return time.Now().Before(f.downUntil[layer])
+}
+
+func (f *Failover) markDown(layer Layer) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.downUntil[layer] = time.Now().Add(f.cooldown)
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
+)
+
+var errEndpointDown = errors.New("endpoint down")
+
+// checkedBackend is a [memoryBackend] with a health check and a price.
+type checkedBackend struct {
+	*memoryBackend
+
+	price     float64
+	healthErr error
+	checks    int
+}
+
+func (c *checkedBackend) HealthCheck(context.Context) error {
+	c.checks++
+	return c.healthErr
+}
+
+func (c *checkedBackend) Price(context.Context) (float64, error) {
+	return c.price, nil
+}
+
+func TestNewFailover(t *testing.T) {
+	backends := []DataAvailability{&memoryBackend{layer: Celestia}}
+
+	_, err := NewFailover(backends, FailoverPolicy{})
+	require.ErrorIs(t, err, ErrNoBackends)
+	_, err = NewFailover(backends, FailoverPolicy{Layers: []FailoverLayer{{Layer: EigenDA}}})
+	require.ErrorIs(t, err, ErrMissingBackend)
+	_, err = NewFailover(backends, FailoverPolicy{Layers: []FailoverLayer{{Layer: Celestia}, {Layer: Celestia}}})
+	require.ErrorIs(t, err, ErrDuplicateLayer)
+}
+
+func TestFailoverRouting(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	ethereum := &checkedBackend{memoryBackend: &memoryBackend{layer: Ethereum}, price: 30}
+	celestia := &checkedBackend{memoryBackend: &memoryBackend{layer: Celestia}, healthErr: errEndpointDown}
+	avail := &memoryBackend{layer: Avail, failures: 1}
+	eigenDA := &memoryBackend{layer: EigenDA}
+	failover, err := NewFailover(
+		[]DataAvailability{ethereum, celestia, avail, eigenDA},
+		FailoverPolicy{
+			Layers: []FailoverLayer{
+				{Layer: Ethereum, MaxPrice: 20},
+				{Layer: Celestia},
+				{Layer: Avail},
+				{Layer: EigenDA},
+			},
+			Cooldown: time.Hour,
+		},
+	)
+	require.NoError(err)
+
+	result, err := failover.Submit(ctx, []byte("rollup batch"))
+	require.NoError(err)
+	require.Equal(EigenDA, result.Layer)
+	require.Equal(EigenDA, result.Receipt.Layer)
+	require.Len(result.Skipped, 3)
+	require.ErrorIs(result.Skipped[0].err, ErrPriceTooHigh)
+	require.InDelta(30, result.Skipped[0].Price, 0)
+	require.ErrorIs(result.Skipped[1].err, ErrUnhealthy)
+	require.ErrorIs(result.Skipped[1].err, errEndpointDown)
+	require.ErrorIs(result.Skipped[2].err, errUnavailable)
+
+	// Celestia and Avail are cooling down, Ethereum is used once its price
+	// drops.
+	ethereum.price = 10
+	result, err = failover.Submit(ctx, []byte("rollup batch"))
+	require.NoError(err)
+	require.Equal(Ethereum, result.Layer)
+	require.Empty(result.Skipped)
+
+	ethereum.price = 30
+	result, err = failover.Submit(ctx, []byte("rollup batch"))
+	require.NoError(err)
+	require.Equal(EigenDA, result.Layer)
+	require.ErrorIs(result.Skipped[1].err, ErrLayerCoolingDown)
+	require.ErrorIs(result.Skipped[2].err, ErrLayerCoolingDown)
+	require.Equal(1, celestia.checks)
+}
+
+func TestFailoverNoLayerAvailable(t *testing.T) {
+	require := require.New(t)
+
+	failover, err := NewFailover(
+		[]DataAvailability{&memoryBackend{layer: Celestia, failures: 1}},
+		FailoverPolicy{Layers: []FailoverLayer{{Layer: Celestia}}},
+	)
+	require.NoError(err)
+
+	result, err := failover.Submit(context.Background(), []byte("rollup batch"))
+	require.ErrorIs(err, ErrNoLayerAvailable)
+	require.ErrorIs(err, errUnavailable)
+	require.Len(result.Skipped, 1)
+}
+
+func TestFailoverFees(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	node := datest.NewEthereum(t, 20*time.Millisecond)
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(err)
+	t.Cleanup(client.Close)
+	celestiaNode := datest.NewCelestia(t)
+
+	ethereum := NewEthereum(client, node.PrivateKey, actions.NewDefaultBlobFeeConfig(), 1)
+	celestia := NewCelestia(celestiaNode.URL, "token", nil, 0)
+	require.NoError(ethereum.(HealthChecker).HealthCheck(ctx))
+	require.NoError(celestia.(HealthChecker).HealthCheck(ctx))
+
+	// The blob base fee is at its minimum of 1 wei.
+	failover, err := NewFailover(
+		[]DataAvailability{ethereum, celestia},
+		FailoverPolicy{
+			Layers: []FailoverLayer{
+				{Layer: Ethereum, MaxPrice: 0.5},
+				{Layer: Celestia, MaxPrice: 0.2},
+			},
+			HealthTimeout: time.Second,
+		},
+	)
+	require.NoError(err)
+
+	result, err := failover.Submit(ctx, []byte("rollup batch"))
+	require.NoError(err)
+	require.Equal(Celestia, result.Layer)
+	require.Equal(celestiaNode.Height(), result.Receipt.Height)
+	require.Len(result.Skipped, 1)
+	require.InDelta(1, result.Skipped[0].Price, 0)
+	require.ErrorIs(result.Skipped[0].err, ErrPriceTooHigh)
+}
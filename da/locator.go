@@ -0,0 +1,92 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+)
+
+const hashLen = 32
+
+// Locator returns what [r.Layer] needs, besides the height, namespace and
+// commitment registered on-chain, to find the blob again:
+//   - Ethereum: hash of every transaction carrying the blobs, in order
+//   - EigenDA: batch header hash | blob index | reference block number
+//   - Avail: block hash | extrinsic index
+//
+// Integers are big endian uint32. Other layers need no locator and nil is
+// returned. A payload spread over several Ethereum transactions does not fit
+// in [storage.MaxLocatorSize] and cannot be located: it has to be sharded.
+func (r *BlobReceipt) Locator() ([]byte, error) {
+	var locator []byte
+	switch r.Layer {
+	case Ethereum:
+		if len(r.TxHash) != hashLen {
+			return nil, fmt.Errorf("%w: no transaction to locate the blob", ErrInvalidReceipt)
+		}
+		locator = append(locator, r.TxHash...)
+		for _, txHash := range r.ExtraTxHashes {
+			locator = append(locator, txHash...)
+		}
+	case EigenDA:
+		if r.Certificate == nil || len(r.Certificate.BatchHeaderHash) != hashLen {
+			return nil, fmt.Errorf("%w: no certificate to locate the blob", ErrInvalidReceipt)
+		}
+		locator = append(locator, r.Certificate.BatchHeaderHash...)
+		locator = binary.BigEndian.AppendUint32(locator, r.Certificate.BlobIndex)
+		locator = binary.BigEndian.AppendUint32(locator, r.Certificate.ReferenceBlockNumber)
+	case Avail:
+		if len(r.BlockHash) != hashLen {
+			return nil, fmt.Errorf("%w: no block hash to locate the blob", ErrInvalidReceipt)
+		}
+		locator = append(locator, r.BlockHash...)
+		locator = binary.BigEndian.AppendUint32(locator, r.Index)
+	default:
+		return nil, nil
+	}
+	if len(locator) > storage.MaxLocatorSize {
+		return nil, fmt.Errorf("%w: %s locator is %d bytes", ErrInvalidReceipt, r.Layer, len(locator))
+	}
+	return locator, nil
+}
+
+// SetLocator restores in [r] the fields encoded by [BlobReceipt.Locator].
+func (r *BlobReceipt) SetLocator(locator []byte) error {
+	switch r.Layer {
+	case Ethereum:
+		if len(locator) == 0 || len(locator)%hashLen != 0 {
+			return fmt.Errorf("%w: ethereum locator is %d bytes", ErrInvalidLocator, len(locator))
+		}
+		r.TxHash = locator[:hashLen]
+		r.ExtraTxHashes = nil
+		for extra := locator[hashLen:]; len(extra) > 0; extra = extra[hashLen:] {
+			r.ExtraTxHashes = append(r.ExtraTxHashes, extra[:hashLen])
+		}
+	case EigenDA:
+		if len(locator) != hashLen+8 {
+			return fmt.Errorf("%w: eigenda locator is %d bytes", ErrInvalidLocator, len(locator))
+		}
+		if r.Certificate == nil {
+			r.Certificate = &actions.EigenDACertificate{}
+		}
+		r.Certificate.BatchHeaderHash = locator[:hashLen]
+		r.Certificate.BlobIndex = binary.BigEndian.Uint32(locator[hashLen:])
+		r.Certificate.ReferenceBlockNumber = binary.BigEndian.Uint32(locator[hashLen+4:])
+	case Avail:
+		if len(locator) != hashLen+4 {
+			return fmt.Errorf("%w: avail locator is %d bytes", ErrInvalidLocator, len(locator))
+		}
+		r.BlockHash = locator[:hashLen]
+		r.Index = binary.BigEndian.Uint32(locator[hashLen:])
+	default:
+		if len(locator) != 0 {
+			return fmt.Errorf("%w: %s has no locator", ErrInvalidLocator, r.Layer)
+		}
+	}
+	return nil
+}
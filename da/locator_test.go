@@ -0,0 +1,61 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/codec"
+)
+
+func TestLocator(t *testing.T) {
+	require := require.New(t)
+
+	ethereum := &BlobReceipt{
+		Layer:  Ethereum,
+		TxHash: bytes.Repeat([]byte{0x03}, 32),
+	}
+	eigenDA := &BlobReceipt{
+		Layer: EigenDA,
+		Certificate: &actions.EigenDACertificate{
+			BatchHeaderHash:      bytes.Repeat([]byte{0x01}, 32),
+			BlobIndex:            7,
+			ReferenceBlockNumber: 1_000,
+		},
+	}
+	avail := &BlobReceipt{
+		Layer:     Avail,
+		BlockHash: bytes.Repeat([]byte{0x02}, 32),
+		Index:     3,
+	}
+	for _, receipt := range []*BlobReceipt{ethereum, eigenDA, avail} {
+		locator, err := receipt.Locator()
+		require.NoError(err)
+		require.LessOrEqual(len(locator), storage.MaxLocatorSize)
+
+		restored := &BlobReceipt{Layer: receipt.Layer}
+		require.NoError(restored.SetLocator(locator))
+		require.Equal(receipt, restored)
+
+		require.ErrorIs(restored.SetLocator(locator[1:]), ErrInvalidLocator)
+	}
+
+	locator, err := (&BlobReceipt{Layer: Celestia}).Locator()
+	require.NoError(err)
+	require.Nil(locator)
+
+	// The transactions following the first one do not fit in a locator.
+	ethereum.ExtraTxHashes = []codec.Bytes{bytes.Repeat([]byte{0x04}, 32)}
+	_, err = ethereum.Locator()
+	require.ErrorIs(err, ErrInvalidReceipt)
+
+	// EigenDA blobs cannot be located before their certificate is issued.
+	_, err = (&BlobReceipt{Layer: EigenDA}).Locator()
+	require.ErrorIs(err, ErrInvalidReceipt)
+}
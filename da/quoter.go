@@ -0,0 +1,98 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+var (
+	_ CostEstimator = (*ethereumQuoter)(nil)
+	_ CostEstimator = (*celestiaQuoter)(nil)
+	_ CostEstimator = (*availQuoter)(nil)
+	_ CostEstimator = (*eigenDAQuoter)(nil)
+)
+
+// Quoter is a layer quoted by a [CostOracle]. Quoters implementing
+// [CostEstimator] are priced, the others are reported as not supported.
+// Every [DataAvailability] is a quoter.
+type Quoter interface {
+	Layer() Layer
+}
+
+// NewQuoter returns a quoter of the layer selected by [config.Layer]. Unlike
+// [New], it only reads public fee data and needs no private key, seed or
+// auth key.
+func NewQuoter(ctx context.Context, config Config) (Quoter, error) {
+	switch config.Layer {
+	case Ethereum:
+		client, err := ethclient.DialContext(ctx, config.Ethereum.RPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to ethereum client: %w", err)
+		}
+		return &ethereumQuoter{client: client}, nil
+	case Celestia:
+		return &celestiaQuoter{gasPrice: config.Celestia.GasPrice}, nil
+	case Avail:
+		return &availQuoter{url: config.Avail.URL}, nil
+	case EigenDA:
+		return &eigenDAQuoter{pricing: config.EigenDA.Pricing}, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownLayer, config.Layer)
+	}
+}
+
+type ethereumQuoter struct {
+	client *ethclient.Client
+}
+
+func (*ethereumQuoter) Layer() Layer {
+	return Ethereum
+}
+
+func (e *ethereumQuoter) EstimateCost(ctx context.Context, size uint64) (*big.Int, error) {
+	return actions.EstimateBlobCost(ctx, e.client, int(size))
+}
+
+type celestiaQuoter struct {
+	gasPrice float64
+}
+
+func (*celestiaQuoter) Layer() Layer {
+	return Celestia
+}
+
+func (c *celestiaQuoter) EstimateCost(_ context.Context, size uint64) (*big.Int, error) {
+	return celestiaCost(size, celestiaGasPrice(c.gasPrice)), nil
+}
+
+type availQuoter struct {
+	url string
+}
+
+func (*availQuoter) Layer() Layer {
+	return Avail
+}
+
+func (a *availQuoter) EstimateCost(ctx context.Context, size uint64) (*big.Int, error) {
+	return actions.EstimateAvailFee(ctx, a.url, int(size))
+}
+
+type eigenDAQuoter struct {
+	pricing actions.EigenDAPricing
+}
+
+func (*eigenDAQuoter) Layer() Layer {
+	return EigenDA
+}
+
+func (e *eigenDAQuoter) EstimateCost(_ context.Context, size uint64) (*big.Int, error) {
+	return e.pricing.Fee(int(size)), nil
+}
@@ -0,0 +1,302 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// RedundancyPolicy configures how a [Dispatcher] posts the same blob to
+// several DA layers.
+type RedundancyPolicy struct {
+	// Layers are the DA layers every blob is posted to.
+	Layers []Layer `json:"layers"`
+	// Quorum is the number of layers that must accept a blob for the
+	// submission to succeed, 0 requires all of them.
+	Quorum int `json:"quorum"`
+	// MaxAttempts is the number of times a failed submission is tried on each
+	// layer, including the first one.
+	MaxAttempts int `json:"max_attempts"`
+	// RetryDelay is the wait between two attempts on the same layer.
+	RetryDelay time.Duration `json:"retry_delay"`
+}
+
+func NewDefaultRedundancyPolicy() RedundancyPolicy {
+	return RedundancyPolicy{
+		MaxAttempts: 3,
+		RetryDelay:  5 * time.Second,
+	}
+}
+
+// quorum returns the number of acceptances required out of [n] layers.
+func (p RedundancyPolicy) quorum(n int) int {
+	if p.Quorum == 0 {
+		return n
+	}
+	return p.Quorum
+}
+
+// Dispatcher posts every blob to a set of DA backends in parallel and
+// succeeds once a quorum of them accepted it.
+type Dispatcher struct {
+	backends    []DataAvailability
+	quorum      int
+	maxAttempts int
+	retryDelay  time.Duration
+}
+
+// NewDispatcher returns a dispatcher posting to [backends] according to
+// [policy]. [policy.Layers] is ignored, every backend must be for a different
+// layer.
+func NewDispatcher(backends []DataAvailability, policy RedundancyPolicy) (*Dispatcher, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+	seen := make(map[Layer]struct{}, len(backends))
+	for _, backend := range backends {
+		if _, ok := seen[backend.Layer()]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateLayer, backend.Layer())
+		}
+		seen[backend.Layer()] = struct{}{}
+	}
+	quorum := policy.quorum(len(backends))
+	if quorum < 1 || quorum > len(backends) {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidQuorum, quorum, len(backends))
+	}
+	return &Dispatcher{
+		backends:    backends,
+		quorum:      quorum,
+		maxAttempts: max(policy.MaxAttempts, 1),
+		retryDelay:  policy.RetryDelay,
+	}, nil
+}
+
+// Layers returns the layers the dispatcher posts to.
+func (d *Dispatcher) Layers() []Layer {
+	layers := make([]Layer, len(d.backends))
+	for i, backend := range d.backends {
+		layers[i] = backend.Layer()
+	}
+	return layers
+}
+
+// Backend returns the backend posting to [layer].
+func (d *Dispatcher) Backend(layer Layer) (DataAvailability, bool) {
+	for _, backend := range d.backends {
+		if backend.Layer() == layer {
+			return backend, true
+		}
+	}
+	return nil, false
+}
+
+// LayerResult is the outcome of posting a blob to one layer.
+type LayerResult struct {
+	Layer    Layer        `json:"layer"`
+	Receipt  *BlobReceipt `json:"receipt,omitempty"`
+	Attempts int          `json:"attempts"`
+	// Done is set once the layer accepted the blob or every attempt failed.
+	Done bool `json:"done"`
+	// Error is the error of the last failed attempt.
+	Error string `json:"error,omitempty"`
+
+	err error
+}
+
+// Dispatch tracks the submission of one blob by a [Dispatcher]. Layers that
+// did not accept the blob when the quorum was reached keep being tried in the
+// background.
+type Dispatch struct {
+	Digest ids.ID
+
+	quorum int
+
+	lock     sync.Mutex
+	results  []*LayerResult
+	accepted int
+	failed   int
+	// reached is closed once [quorum] layers accepted the blob, missed once
+	// too many failed for that to happen.
+	reached   chan struct{}
+	missed    chan struct{}
+	done      chan struct{}
+	remaining int
+}
+
+// Submit posts [data] to every backend in parallel and returns once
+// [d.quorum] of them accepted it. If the quorum cannot be reached anymore,
+// the returned error wraps [ErrQuorumNotMet] and the error of every failed
+// layer. Laggards keep being retried under [ctx] after Submit returns.
+func (d *Dispatcher) Submit(ctx context.Context, data []byte) (*Dispatch, error) {
+	dispatch := &Dispatch{
+		Digest:    sha256.Sum256(data),
+		quorum:    d.quorum,
+		results:   make([]*LayerResult, len(d.backends)),
+		reached:   make(chan struct{}),
+		missed:    make(chan struct{}),
+		done:      make(chan struct{}),
+		remaining: len(d.backends),
+	}
+	for i, backend := range d.backends {
+		dispatch.results[i] = &LayerResult{Layer: backend.Layer()}
+	}
+	for i, backend := range d.backends {
+		go d.post(ctx, dispatch, i, backend, data)
+	}
+
+	select {
+	case <-dispatch.reached:
+		return dispatch, nil
+	case <-dispatch.missed:
+		return dispatch, dispatch.quorumError()
+	case <-ctx.Done():
+		return dispatch, ctx.Err()
+	}
+}
+
+// post submits [data] to [backend] until it succeeds or [d.maxAttempts] is
+// reached, and records the outcome as the result [i] of [dispatch].
+func (d *Dispatcher) post(ctx context.Context, dispatch *Dispatch, i int, backend DataAvailability, data []byte) {
+	for attempt := 1; ; attempt++ {
+		receipt, err := backend.Submit(ctx, data)
+		last := err == nil || attempt >= d.maxAttempts || ctx.Err() != nil
+		dispatch.record(i, attempt, receipt, err, last)
+		if last {
+			return
+		}
+		select {
+		case <-time.After(d.retryDelay):
+		case <-ctx.Done():
+			dispatch.record(i, attempt, nil, ctx.Err(), true)
+			return
+		}
+	}
+}
+
+func (d *Dispatch) record(i int, attempts int, receipt *BlobReceipt, err error, last bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	result := d.results[i]
+	result.Attempts = attempts
+	result.Receipt = receipt
+	result.err = err
+	result.Error = ""
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if !last {
+		return
+	}
+	result.Done = true
+	if err == nil {
+		d.accepted++
+		if d.accepted == d.quorum {
+			close(d.reached)
+		}
+	} else {
+		d.failed++
+		if d.failed == len(d.results)-d.quorum+1 {
+			close(d.missed)
+		}
+	}
+	d.remaining--
+	if d.remaining == 0 {
+		close(d.done)
+	}
+}
+
+// Reached reports whether the quorum of layers accepted the blob.
+func (d *Dispatch) Reached() bool {
+	select {
+	case <-d.reached:
+		return true
+	default:
+		return false
+	}
+}
+
+// Results returns a snapshot of the result of every layer, in the order of
+// the backends of the dispatcher.
+func (d *Dispatch) Results() []LayerResult {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	results := make([]LayerResult, len(d.results))
+	for i, result := range d.results {
+		results[i] = *result
+	}
+	return results
+}
+
+// Receipts returns the receipts of the layers that accepted the blob so far.
+func (d *Dispatch) Receipts() []*BlobReceipt {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var receipts []*BlobReceipt
+	for _, result := range d.results {
+		if result.Receipt != nil {
+			receipts = append(receipts, result.Receipt)
+		}
+	}
+	return receipts
+}
+
+// Laggards returns the layers that did not accept the blob so far, whether
+// they are still being tried or failed.
+func (d *Dispatch) Laggards() []Layer {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	var layers []Layer
+	for _, result := range d.results {
+		if result.Receipt == nil {
+			layers = append(layers, result.Layer)
+		}
+	}
+	return layers
+}
+
+// Wait blocks until every layer accepted the blob or ran out of attempts. It
+// returns the errors of the layers that failed.
+func (d *Dispatch) Wait(ctx context.Context) error {
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.layerErrors()
+}
+
+func (d *Dispatch) quorumError() error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return fmt.Errorf("%w: %d of %d layers accepted the blob, %d required: %w",
+		ErrQuorumNotMet, d.accepted, len(d.results), d.quorum, d.layerErrors())
+}
+
+// layerErrors joins the errors of the layers that failed. [d.lock] must be
+// held.
+func (d *Dispatch) layerErrors() error {
+	var errs []error
+	for _, result := range d.results {
+		if result.Done && result.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", result.Layer, result.err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("layer unavailable")
+
+// memoryBackend accepts blobs after failing [failures] times. Submissions
+// block until [release] is closed, if set.
+type memoryBackend struct {
+	layer    Layer
+	failures int
+	release  chan struct{}
+
+	lock     sync.Mutex
+	attempts int
+}
+
+func (m *memoryBackend) Layer() Layer {
+	return m.layer
+}
+
+func (m *memoryBackend) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	if m.release != nil {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.attempts++
+	if m.attempts <= m.failures {
+		return nil, errUnavailable
+	}
+	receipt := newReceipt(m.layer, data)
+	receipt.Status = StatusFinalized
+	return receipt, nil
+}
+
+func (*memoryBackend) GetStatus(context.Context, *BlobReceipt) (Status, error) {
+	return StatusFinalized, nil
+}
+
+func (*memoryBackend) Retrieve(context.Context, *BlobReceipt) ([]byte, error) {
+	return nil, ErrRetrieveNotSupported
+}
+
+func (*memoryBackend) Verify(context.Context, *BlobReceipt) error {
+	return nil
+}
+
+func TestNewDispatcher(t *testing.T) {
+	celestia := &memoryBackend{layer: Celestia}
+	eigenDA := &memoryBackend{layer: EigenDA}
+
+	tests := []struct {
+		name     string
+		backends []DataAvailability
+		quorum   int
+		err      error
+	}{
+		{name: "all", backends: []DataAvailability{celestia, eigenDA}},
+		{name: "one of two", backends: []DataAvailability{celestia, eigenDA}, quorum: 1},
+		{name: "no backends", err: ErrNoBackends},
+		{name: "duplicate layer", backends: []DataAvailability{celestia, celestia}, err: ErrDuplicateLayer},
+		{name: "quorum too large", backends: []DataAvailability{celestia}, quorum: 2, err: ErrInvalidQuorum},
+		{name: "negative quorum", backends: []DataAvailability{celestia}, quorum: -1, err: ErrInvalidQuorum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDispatcher(tt.backends, RedundancyPolicy{Quorum: tt.quorum})
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestDispatcherQuorum(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	celestia := &memoryBackend{layer: Celestia}
+	ethereum := &memoryBackend{layer: Ethereum, failures: 1}
+	eigenDA := &memoryBackend{layer: EigenDA, release: release}
+	dispatcher, err := NewDispatcher(
+		[]DataAvailability{celestia, ethereum, eigenDA},
+		RedundancyPolicy{Quorum: 2, MaxAttempts: 2, RetryDelay: time.Millisecond},
+	)
+	require.NoError(err)
+
+	data := []byte("rollup batch")
+	dispatch, err := dispatcher.Submit(ctx, data)
+	require.NoError(err)
+	require.True(dispatch.Reached())
+	require.Len(dispatch.Receipts(), 2)
+	require.Equal([]Layer{EigenDA}, dispatch.Laggards())
+
+	results := dispatch.Results()
+	require.Equal(1, results[0].Attempts)
+	require.Equal(2, results[1].Attempts)
+	require.False(results[2].Done)
+
+	close(release)
+	require.NoError(dispatch.Wait(ctx))
+	require.Empty(dispatch.Laggards())
+	for _, receipt := range dispatch.Receipts() {
+		require.Equal(dispatch.Digest, receipt.Digest)
+	}
+}
+
+func TestDispatcherQuorumNotMet(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	defer close(release)
+	dispatcher, err := NewDispatcher(
+		[]DataAvailability{
+			&memoryBackend{layer: Celestia, failures: 2},
+			&memoryBackend{layer: Ethereum, failures: 2},
+			&memoryBackend{layer: EigenDA, release: release},
+		},
+		RedundancyPolicy{Quorum: 2, MaxAttempts: 2, RetryDelay: time.Millisecond},
+	)
+	require.NoError(err)
+
+	// The quorum is missed without waiting for EigenDA.
+	dispatch, err := dispatcher.Submit(ctx, []byte("rollup batch"))
+	require.ErrorIs(err, ErrQuorumNotMet)
+	require.ErrorIs(err, errUnavailable)
+	require.False(dispatch.Reached())
+	require.Empty(dispatch.Receipts())
+
+	results := dispatch.Results()
+	require.Equal(2, results[0].Attempts)
+	require.Equal(errUnavailable.Error(), results[0].Error)
+}
@@ -0,0 +1,294 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/klauspost/reedsolomon"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+)
+
+// DefaultShardSize fills a single EIP-4844 blob, whose first 8 bytes hold
+// the length of its payload. Stages wrapping the backends of a [Sharder],
+// such as [NewEncrypted], add to the posted shards and have to be taken off
+// the shard size.
+const DefaultShardSize = actions.UsableBytesPerBlob - 8
+
+// ShardingPolicy configures how a [Sharder] splits payloads.
+type ShardingPolicy struct {
+	// Layers are the DA layers shards are spread over, in turn.
+	Layers []Layer `json:"layers"`
+	// ShardSize is the largest shard posted, it should fit in a single blob
+	// of every layer.
+	ShardSize uint64 `json:"shard_size"`
+	// ParityRatio is the number of parity shards per data shard. Any set of
+	// shards as large as the data shards restores the payload, so losing a
+	// layer out of n is tolerated with a ratio of at least 1/(n-1).
+	ParityRatio float64 `json:"parity_ratio"`
+}
+
+func NewDefaultShardingPolicy() ShardingPolicy {
+	return ShardingPolicy{
+		ShardSize:   DefaultShardSize,
+		ParityRatio: 0.5,
+	}
+}
+
+// Sharder splits payloads into Reed-Solomon coded shards and posts them to
+// a set of DA backends.
+type Sharder struct {
+	backends    []DataAvailability
+	shardSize   uint64
+	parityRatio float64
+}
+
+// NewSharder returns a sharder spreading shards over [backends] according to
+// [policy]. [policy.Layers] is ignored.
+func NewSharder(backends []DataAvailability, policy ShardingPolicy) (*Sharder, error) {
+	if len(backends) == 0 {
+		return nil, ErrNoBackends
+	}
+	if policy.ShardSize == 0 {
+		return nil, fmt.Errorf("%w: shard size is 0", ErrInvalidShardingPolicy)
+	}
+	if policy.ParityRatio < 0 || math.IsNaN(policy.ParityRatio) {
+		return nil, fmt.Errorf("%w: parity ratio %g", ErrInvalidShardingPolicy, policy.ParityRatio)
+	}
+	return &Sharder{
+		backends:    backends,
+		shardSize:   policy.ShardSize,
+		parityRatio: policy.ParityRatio,
+	}, nil
+}
+
+// ShardRef locates one shard of a payload.
+type ShardRef struct {
+	Index int `json:"index"`
+	// Digest is the sha256 of the shard.
+	Digest  ids.ID       `json:"digest"`
+	Receipt *BlobReceipt `json:"receipt"`
+}
+
+// ShardManifest lists the shards a payload was split into. The first
+// [DataShards] shards hold the payload, the others its parity.
+type ShardManifest struct {
+	// Digest is the sha256 of the payload, and Size its size.
+	Digest       ids.ID     `json:"digest"`
+	Size         uint64     `json:"size"`
+	DataShards   int        `json:"data_shards"`
+	ParityShards int        `json:"parity_shards"`
+	Shards       []ShardRef `json:"shards"`
+}
+
+// shardCounts returns the number of data and parity shards of a payload of
+// [size] bytes.
+func (s *Sharder) shardCounts(size uint64) (int, int) {
+	data := int((size + s.shardSize - 1) / s.shardSize)
+	return data, int(math.Ceil(float64(data) * s.parityRatio))
+}
+
+// ShardSize returns the largest shard posted. Smaller payloads fit in a
+// single shard.
+func (s *Sharder) ShardSize() uint64 {
+	return s.shardSize
+}
+
+// Shards returns the number of shards, data and parity, a payload of [size]
+// bytes is split into.
+func (s *Sharder) Shards(size uint64) int {
+	data, parity := s.shardCounts(size)
+	return data + parity
+}
+
+// encode splits [data] into its data shards followed by its parity shards.
+func (s *Sharder) encode(data []byte) ([][]byte, int, int, error) {
+	if len(data) == 0 {
+		return nil, 0, 0, ErrEmptyPayload
+	}
+	dataShards, parityShards := s.shardCounts(uint64(len(data)))
+	enc, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: %w", ErrInvalidShardingPolicy, err)
+	}
+	// Split pads the last data shard and may use the spare capacity of its
+	// input, which must not be the caller's.
+	shards, err := enc.Split(data[:len(data):len(data)])
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if err := enc.Encode(shards); err != nil {
+		return nil, 0, 0, err
+	}
+	return shards, dataShards, parityShards, nil
+}
+
+// Submit splits [data] into shards and posts them in parallel, shard i to
+// backend i modulo the number of backends. If a shard is not accepted, the
+// returned error wraps [ErrShardNotPosted] and the manifest is returned
+// anyway, with the receipts of the shards that were posted, so that
+// [Resubmit] only posts the missing ones.
+func (s *Sharder) Submit(ctx context.Context, data []byte) (*ShardManifest, error) {
+	shards, dataShards, parityShards, err := s.encode(data)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &ShardManifest{
+		Digest:       sha256.Sum256(data),
+		Size:         uint64(len(data)),
+		DataShards:   dataShards,
+		ParityShards: parityShards,
+		Shards:       make([]ShardRef, len(shards)),
+	}
+	for i, shard := range shards {
+		manifest.Shards[i] = ShardRef{Index: i, Digest: sha256.Sum256(shard)}
+	}
+	return manifest, s.post(ctx, manifest, shards)
+}
+
+// Resubmit posts the shards of [manifest] that have no receipt yet. [data]
+// must be the payload [manifest] was returned for by [Submit].
+func (s *Sharder) Resubmit(ctx context.Context, data []byte, manifest *ShardManifest) error {
+	if ids.ID(sha256.Sum256(data)) != manifest.Digest || uint64(len(data)) != manifest.Size {
+		return ErrDigestMismatch
+	}
+	shards, dataShards, parityShards, err := s.encode(data)
+	if err != nil {
+		return err
+	}
+	if manifest.DataShards != dataShards || manifest.ParityShards != parityShards || len(manifest.Shards) != len(shards) {
+		return ErrInvalidManifest
+	}
+	return s.post(ctx, manifest, shards)
+}
+
+// post posts in parallel the [shards] missing a receipt in [manifest] and
+// records the receipt of each accepted shard.
+func (s *Sharder) post(ctx context.Context, manifest *ShardManifest, shards [][]byte) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(shards))
+	)
+	for i, shard := range shards {
+		if manifest.Shards[i].Receipt != nil {
+			continue
+		}
+		backend := s.backends[i%len(s.backends)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			receipt, err := backend.Submit(ctx, shard)
+			if err != nil {
+				errs[i] = fmt.Errorf("shard %d on %s: %w", i, backend.Layer(), err)
+				return
+			}
+			manifest.Shards[i].Receipt = receipt
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%w: %w", ErrShardNotPosted, err)
+	}
+	return nil
+}
+
+// GetStatus returns the status of the shard of [receipt] on the backend of
+// its layer.
+func (s *Sharder) GetStatus(ctx context.Context, receipt *BlobReceipt) (Status, error) {
+	for _, backend := range s.backends {
+		if backend.Layer() == receipt.Layer {
+			return backend.GetStatus(ctx, receipt)
+		}
+	}
+	return StatusUnknown, fmt.Errorf("%w: %s", ErrMissingBackend, receipt.Layer)
+}
+
+// Retrieve reads the shards of [manifest] back in parallel and restores the
+// payload from the first [manifest.DataShards] shards matching their digest.
+// The returned error wraps [ErrNotEnoughShards] and the error of every
+// shard if too few were retrieved.
+func (s *Sharder) Retrieve(ctx context.Context, manifest *ShardManifest) ([]byte, error) {
+	if manifest.DataShards < 1 || len(manifest.Shards) != manifest.DataShards+manifest.ParityShards {
+		return nil, ErrInvalidManifest
+	}
+	enc, err := reedsolomon.New(manifest.DataShards, manifest.ParityShards)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+	for i, ref := range manifest.Shards {
+		if ref.Index != i || ref.Receipt == nil {
+			return nil, ErrInvalidManifest
+		}
+	}
+	backends := make(map[Layer]DataAvailability, len(s.backends))
+	for _, backend := range s.backends {
+		backends[backend.Layer()] = backend
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type retrieved struct {
+		index int
+		shard []byte
+		err   error
+	}
+	results := make(chan retrieved, len(manifest.Shards))
+	for i, ref := range manifest.Shards {
+		backend, ok := backends[ref.Receipt.Layer]
+		if !ok {
+			results <- retrieved{index: i, err: fmt.Errorf("%w: %s", ErrMissingBackend, ref.Receipt.Layer)}
+			continue
+		}
+		go func() {
+			shard, err := backend.Retrieve(ctx, ref.Receipt)
+			if err == nil && ids.ID(sha256.Sum256(shard)) != ref.Digest {
+				err = ErrDigestMismatch
+			}
+			results <- retrieved{index: i, shard: shard, err: err}
+		}()
+	}
+
+	var (
+		shards = make([][]byte, len(manifest.Shards))
+		found  int
+		errs   []error
+	)
+	for range manifest.Shards {
+		result := <-results
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("shard %d: %w", result.index, result.err))
+			continue
+		}
+		shards[result.index] = result.shard
+		found++
+		if found == manifest.DataShards {
+			break
+		}
+	}
+	if found < manifest.DataShards {
+		return nil, fmt.Errorf("%w: %d of %d: %w", ErrNotEnoughShards, found, manifest.DataShards, errors.Join(errs...))
+	}
+
+	if err := enc.ReconstructData(shards); err != nil {
+		return nil, err
+	}
+	var data bytes.Buffer
+	if err := enc.Join(&data, shards, int(manifest.Size)); err != nil {
+		return nil, err
+	}
+	if ids.ID(sha256.Sum256(data.Bytes())) != manifest.Digest {
+		return nil, ErrDigestMismatch
+	}
+	return data.Bytes(), nil
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package da
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+)
+
+// storedBackend is a [memoryBackend] serving the blobs it accepted, unless
+// they were [lost].
+type storedBackend struct {
+	*memoryBackend
+
+	lost bool
+
+	blobsLock sync.Mutex
+	blobs     map[ids.ID][]byte
+}
+
+func newStoredBackend(layer Layer) *storedBackend {
+	return &storedBackend{
+		memoryBackend: &memoryBackend{layer: layer},
+		blobs:         make(map[ids.ID][]byte),
+	}
+}
+
+func (s *storedBackend) Submit(ctx context.Context, data []byte) (*BlobReceipt, error) {
+	receipt, err := s.memoryBackend.Submit(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	s.blobsLock.Lock()
+	defer s.blobsLock.Unlock()
+
+	s.blobs[receipt.Digest] = data
+	return receipt, nil
+}
+
+func (s *storedBackend) Retrieve(_ context.Context, receipt *BlobReceipt) ([]byte, error) {
+	s.blobsLock.Lock()
+	defer s.blobsLock.Unlock()
+
+	data, ok := s.blobs[receipt.Digest]
+	if s.lost || !ok {
+		return nil, errUnavailable
+	}
+	return data, nil
+}
+
+func TestNewSharder(t *testing.T) {
+	backends := []DataAvailability{&memoryBackend{layer: Celestia}}
+
+	_, err := NewSharder(nil, NewDefaultShardingPolicy())
+	require.ErrorIs(t, err, ErrNoBackends)
+	_, err = NewSharder(backends, ShardingPolicy{})
+	require.ErrorIs(t, err, ErrInvalidShardingPolicy)
+	_, err = NewSharder(backends, ShardingPolicy{ShardSize: 1, ParityRatio: -1})
+	require.ErrorIs(t, err, ErrInvalidShardingPolicy)
+}
+
+func TestSharder(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+
+	celestia := newStoredBackend(Celestia)
+	avail := newStoredBackend(Avail)
+	eigenDA := newStoredBackend(EigenDA)
+	sharder, err := NewSharder(
+		[]DataAvailability{celestia, avail, eigenDA},
+		ShardingPolicy{ShardSize: 1000, ParityRatio: 0.5},
+	)
+	require.NoError(err)
+
+	data := make([]byte, 3500)
+	_, err = rand.Read(data)
+	require.NoError(err)
+	manifest, err := sharder.Submit(ctx, data)
+	require.NoError(err)
+	require.Equal(ids.ID(sha256.Sum256(data)), manifest.Digest)
+	require.Equal(uint64(len(data)), manifest.Size)
+	require.Equal(4, manifest.DataShards)
+	require.Equal(2, manifest.ParityShards)
+	require.Len(manifest.Shards, 6)
+	for i, ref := range manifest.Shards {
+		require.Equal(i, ref.Index)
+		require.Equal(ref.Digest, ref.Receipt.Digest)
+		require.Equal([]Layer{Celestia, Avail, EigenDA}[i%3], ref.Receipt.Layer)
+	}
+	require.Equal(2, celestia.attempts)
+
+	retrieved, err := sharder.Retrieve(ctx, manifest)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+
+	// Losing any two shards is tolerated.
+	eigenDA.lost = true
+	retrieved, err = sharder.Retrieve(ctx, manifest)
+	require.NoError(err)
+	require.Equal(data, retrieved)
+
+	avail.lost = true
+	_, err = sharder.Retrieve(ctx, manifest)
+	require.ErrorIs(err, ErrNotEnoughShards)
+	require.ErrorIs(err, errUnavailable)
+
+	// A shard not matching its digest is ignored.
+	avail.lost = false
+	avail.blobs[manifest.Shards[1].Digest] = []byte("corrupted")
+	_, err = sharder.Retrieve(ctx, manifest)
+	require.ErrorIs(err, ErrNotEnoughShards)
+	require.ErrorIs(err, ErrDigestMismatch)
+}
+
+func TestSharderSubmitFailure(t *testing.T) {
+	require := require.New(t)
+
+	ctx := context.Background()
+
+	celestia := newStoredBackend(Celestia)
+	eigenDA := &memoryBackend{layer: EigenDA, failures: 1}
+	sharder, err := NewSharder(
+		[]DataAvailability{celestia, eigenDA},
+		ShardingPolicy{ShardSize: 10},
+	)
+	require.NoError(err)
+
+	_, err = sharder.Submit(ctx, nil)
+	require.ErrorIs(err, ErrEmptyPayload)
+
+	// The manifest keeps the receipt of the shard that was posted.
+	data := make([]byte, 20)
+	manifest, err := sharder.Submit(ctx, data)
+	require.ErrorIs(err, ErrShardNotPosted)
+	require.ErrorIs(err, errUnavailable)
+	require.NotNil(manifest.Shards[0].Receipt)
+	require.Nil(manifest.Shards[1].Receipt)
+
+	// Only the missing shard is posted again.
+	require.ErrorIs(sharder.Resubmit(ctx, data[1:], manifest), ErrDigestMismatch)
+	require.NoError(sharder.Resubmit(ctx, data, manifest))
+	require.Equal(1, celestia.attempts)
+	require.Equal(2, eigenDA.attempts)
+	require.Equal(EigenDA, manifest.Shards[1].Receipt.Layer)
+
+	status, err := sharder.GetStatus(ctx, manifest.Shards[1].Receipt)
+	require.NoError(err)
+	require.Equal(StatusFinalized, status)
+	_, err = sharder.GetStatus(ctx, &BlobReceipt{Layer: Avail})
+	require.ErrorIs(err, ErrMissingBackend)
+}
+
+func TestSharderInvalidManifest(t *testing.T) {
+	require := require.New(t)
+
+	sharder, err := NewSharder([]DataAvailability{newStoredBackend(Celestia)}, NewDefaultShardingPolicy())
+	require.NoError(err)
+	manifest, err := sharder.Submit(context.Background(), []byte("rollup batch"))
+	require.NoError(err)
+	require.Equal(1, manifest.DataShards)
+	require.Equal(1, manifest.ParityShards)
+
+	manifest.Shards = manifest.Shards[1:]
+	_, err = sharder.Retrieve(context.Background(), manifest)
+	require.ErrorIs(err, ErrInvalidManifest)
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	MaxCommitmentSize = 64
+	MaxNamespaceSize  = 29 // Celestia namespace version + ID
+	CiphertextSize    = sha256.Size
+	// MaxLocatorSize fits an EigenDA batch header hash, blob index and
+	// reference block number, or a single Ethereum transaction hash.
+	MaxLocatorSize = 40
+
+	maxBlobRecordSize = codec.AddressLen + consts.ByteLen + consts.Uint64Len +
+		consts.IntLen + MaxNamespaceSize + consts.IntLen + MaxCommitmentSize +
+		consts.Uint64Len + consts.Int64Len + consts.IntLen + CiphertextSize +
+		consts.IntLen + MaxLocatorSize
+)
+
+// BlobRecord describes where the data of a blob registered on-chain lives.
+type BlobRecord struct {
+	Submitter codec.Address `json:"submitter"`
+	Layer     uint8         `json:"layer"`
+
+	// Locator of the blob on [Layer]: the Celestia height, namespace and
+	// share commitment, the Ethereum versioned hash (or the sha256 of the
+	// versioned hashes of a payload spanning several blobs), ...
+	Height     uint64      `json:"height"`
+	Namespace  codec.Bytes `json:"namespace"`
+	Commitment codec.Bytes `json:"commitment"`
+
+	Size      uint64 `json:"size"`
+	Timestamp int64  `json:"timestamp"`
+
+	// Ciphertext is the sha256 of the encrypted payload posted to [Layer],
+	// if the blob was encrypted.
+	Ciphertext codec.Bytes `json:"ciphertext,omitempty"`
+
+	// Locator holds what [Layer] needs besides [Height], [Namespace] and
+	// [Commitment] to find the blob, such as the Avail block hash and
+	// extrinsic index.
+	Locator codec.Bytes `json:"locator,omitempty"`
+}
+
+// BlobID identifies a blob by the DA layer it was posted to and the
+// layer-specific commitment to its contents.
+func BlobID(layer uint8, commitment []byte) ids.ID {
+	return sha256.Sum256(append([]byte{layer}, commitment...))
+}
+
+// [blobPrefix] + [blobID]
+func BlobKey(blobID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = blobPrefix
+	copy(k[1:], blobID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], BlobChunks)
+	return
+}
+
+// GetBlob returns the record of [blobID]. If the blob was never registered,
+// the second return value is false.
+func GetBlob(
+	ctx context.Context,
+	im state.Immutable,
+	blobID ids.ID,
+) (*BlobRecord, bool, error) {
+	return innerGetBlob(im.GetValue(ctx, BlobKey(blobID)))
+}
+
+// Used to serve RPC queries
+func GetBlobFromState(
+	ctx context.Context,
+	f ReadState,
+	blobID ids.ID,
+) (*BlobRecord, bool, error) {
+	values, errs := f(ctx, [][]byte{BlobKey(blobID)})
+	return innerGetBlob(values[0], errs[0])
+}
+
+func innerGetBlob(v []byte, err error) (*BlobRecord, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	record, err := unmarshalBlobRecord(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return record, true, nil
+}
+
+func SetBlob(
+	ctx context.Context,
+	mu state.Mutable,
+	blobID ids.ID,
+	record *BlobRecord,
+) error {
+	v, err := marshalBlobRecord(record)
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, BlobKey(blobID), v)
+}
+
+func marshalBlobRecord(record *BlobRecord) ([]byte, error) {
+	p := codec.NewWriter(maxBlobRecordSize, maxBlobRecordSize)
+	p.PackAddress(record.Submitter)
+	p.PackByte(record.Layer)
+	p.PackUint64(record.Height)
+	p.PackBytes(record.Namespace)
+	p.PackBytes(record.Commitment)
+	p.PackUint64(record.Size)
+	p.PackInt64(record.Timestamp)
+	p.PackBytes(record.Ciphertext)
+	p.PackBytes(record.Locator)
+	return p.Bytes(), p.Err()
+}
+
+func unmarshalBlobRecord(v []byte) (*BlobRecord, error) {
+	var (
+		p      = codec.NewReader(v, maxBlobRecordSize)
+		record BlobRecord
+	)
+	p.UnpackAddress(&record.Submitter)
+	record.Layer = p.UnpackByte()
+	record.Height = p.UnpackUint64(false)
+	p.UnpackBytes(MaxNamespaceSize, false, (*[]byte)(&record.Namespace))
+	p.UnpackBytes(MaxCommitmentSize, true, (*[]byte)(&record.Commitment))
+	record.Size = p.UnpackUint64(true)
+	record.Timestamp = p.UnpackInt64(false)
+	p.UnpackBytes(CiphertextSize, false, (*[]byte)(&record.Ciphertext))
+	p.UnpackBytes(MaxLocatorSize, false, (*[]byte)(&record.Locator))
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidBlobRecord
+	}
+	return &record, nil
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestBlobRecord(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := chaintest.NewInMemoryStore()
+
+	commitment := make([]byte, MaxCommitmentSize)
+	blobID := BlobID(1, commitment)
+	_, exists, err := GetBlob(ctx, store, blobID)
+	require.NoError(err)
+	require.False(exists)
+
+	record := &BlobRecord{
+		Submitter:  codectest.NewRandomAddress(),
+		Layer:      1,
+		Height:     10,
+		Namespace:  make([]byte, MaxNamespaceSize),
+		Commitment: commitment,
+		Size:       4096,
+		Timestamp:  1000,
+		Ciphertext: make([]byte, CiphertextSize),
+		Locator:    make([]byte, MaxLocatorSize),
+	}
+	require.NoError(SetBlob(ctx, store, blobID, record))
+
+	stored, exists, err := GetBlob(ctx, store, blobID)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(record, stored)
+
+	v, err := store.GetValue(ctx, BlobKey(blobID))
+	require.NoError(err)
+	require.LessOrEqual(len(v), maxBlobRecordSize)
+	chunks := len(v)/64 + 1
+	require.LessOrEqual(chunks, int(BlobChunks))
+}
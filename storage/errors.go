@@ -8,4 +8,10 @@ import "errors"
 var (
 	ErrInvalidAddress = errors.New("invalid address")
 	ErrInvalidBalance = errors.New("invalid balance")
+
+	ErrBlobAlreadyRegistered = errors.New("blob already registered")
+	ErrInvalidBlobRecord     = errors.New("invalid blob record")
+
+	ErrManifestAlreadyRegistered = errors.New("manifest already registered")
+	ErrInvalidManifestRecord     = errors.New("invalid manifest record")
 )
@@ -0,0 +1,171 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/state"
+)
+
+const (
+	MaxManifestBlobs = 64
+
+	maxManifestRecordSize = codec.AddressLen + ids.IDLen + consts.IntLen +
+		MaxManifestBlobs*ids.IDLen + consts.Uint64Len + consts.Int64Len
+)
+
+// ManifestRecord groups registered blobs, possibly on different layers, into
+// a single ordered batch.
+type ManifestRecord struct {
+	Submitter codec.Address `json:"submitter"`
+	// Root is the [ManifestRoot] of [Blobs].
+	Root  ids.ID   `json:"root"`
+	Blobs []ids.ID `json:"blobs"`
+
+	// Size is the total size of [Blobs].
+	Size      uint64 `json:"size"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ManifestRoot returns the root of the binary sha256 Merkle tree whose
+// leaves are [blobIDs] in order. Leaves and inner nodes are hashed with
+// distinct prefixes, and a node without a sibling moves up a level as is.
+func ManifestRoot(blobIDs []ids.ID) ids.ID {
+	if len(blobIDs) == 0 {
+		return ids.Empty
+	}
+	level := make([]ids.ID, len(blobIDs))
+	for i, blobID := range blobIDs {
+		level[i] = sha256.Sum256(append([]byte{0x00}, blobID[:]...))
+	}
+	for len(level) > 1 {
+		next := level[:0]
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			node := make([]byte, 0, 1+2*ids.IDLen)
+			node = append(node, 0x01)
+			node = append(node, level[i][:]...)
+			node = append(node, level[i+1][:]...)
+			next = append(next, sha256.Sum256(node))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// ManifestID identifies a manifest by its submitter and root, so a batch is
+// referenced by a single ID.
+func ManifestID(submitter codec.Address, root ids.ID) ids.ID {
+	return sha256.Sum256(append(submitter[:], root[:]...))
+}
+
+// [manifestPrefix] + [manifestID]
+func ManifestKey(manifestID ids.ID) (k []byte) {
+	k = make([]byte, 1+ids.IDLen+consts.Uint16Len)
+	k[0] = manifestPrefix
+	copy(k[1:], manifestID[:])
+	binary.BigEndian.PutUint16(k[1+ids.IDLen:], ManifestChunks)
+	return
+}
+
+// GetManifest returns the record of [manifestID]. If the manifest was never
+// registered, the second return value is false.
+func GetManifest(
+	ctx context.Context,
+	im state.Immutable,
+	manifestID ids.ID,
+) (*ManifestRecord, bool, error) {
+	return innerGetManifest(im.GetValue(ctx, ManifestKey(manifestID)))
+}
+
+// Used to serve RPC queries
+func GetManifestFromState(
+	ctx context.Context,
+	f ReadState,
+	manifestID ids.ID,
+) (*ManifestRecord, bool, error) {
+	values, errs := f(ctx, [][]byte{ManifestKey(manifestID)})
+	return innerGetManifest(values[0], errs[0])
+}
+
+func innerGetManifest(v []byte, err error) (*ManifestRecord, bool, error) {
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	record, err := unmarshalManifestRecord(v)
+	if err != nil {
+		return nil, false, err
+	}
+	return record, true, nil
+}
+
+func SetManifest(
+	ctx context.Context,
+	mu state.Mutable,
+	manifestID ids.ID,
+	record *ManifestRecord,
+) error {
+	v, err := marshalManifestRecord(record)
+	if err != nil {
+		return err
+	}
+	return mu.Insert(ctx, ManifestKey(manifestID), v)
+}
+
+func marshalManifestRecord(record *ManifestRecord) ([]byte, error) {
+	if len(record.Blobs) > MaxManifestBlobs {
+		return nil, ErrInvalidManifestRecord
+	}
+	p := codec.NewWriter(maxManifestRecordSize, maxManifestRecordSize)
+	p.PackAddress(record.Submitter)
+	p.PackID(record.Root)
+	p.PackInt(uint32(len(record.Blobs)))
+	for _, blobID := range record.Blobs {
+		p.PackID(blobID)
+	}
+	p.PackUint64(record.Size)
+	p.PackInt64(record.Timestamp)
+	return p.Bytes(), p.Err()
+}
+
+func unmarshalManifestRecord(v []byte) (*ManifestRecord, error) {
+	var (
+		p      = codec.NewReader(v, maxManifestRecordSize)
+		record ManifestRecord
+	)
+	p.UnpackAddress(&record.Submitter)
+	p.UnpackID(true, &record.Root)
+	count := p.UnpackInt(true)
+	if count > MaxManifestBlobs {
+		return nil, ErrInvalidManifestRecord
+	}
+	record.Blobs = make([]ids.ID, count)
+	for i := range record.Blobs {
+		p.UnpackID(true, &record.Blobs[i])
+	}
+	record.Size = p.UnpackUint64(true)
+	record.Timestamp = p.UnpackInt64(false)
+	if err := p.Err(); err != nil {
+		return nil, err
+	}
+	if !p.Empty() {
+		return nil, ErrInvalidManifestRecord
+	}
+	return &record, nil
+}
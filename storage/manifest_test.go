@@ -0,0 +1,70 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk/chain/chaintest"
+	"github.com/ava-labs/hypersdk/codec/codectest"
+)
+
+func TestManifestRecord(t *testing.T) {
+	require := require.New(t)
+	ctx := context.Background()
+	store := chaintest.NewInMemoryStore()
+
+	blobs := make([]ids.ID, MaxManifestBlobs)
+	for i := range blobs {
+		blobs[i] = ids.GenerateTestID()
+	}
+	submitter := codectest.NewRandomAddress()
+	root := ManifestRoot(blobs)
+	manifestID := ManifestID(submitter, root)
+	_, exists, err := GetManifest(ctx, store, manifestID)
+	require.NoError(err)
+	require.False(exists)
+
+	record := &ManifestRecord{
+		Submitter: submitter,
+		Root:      root,
+		Blobs:     blobs,
+		Size:      1 << 20,
+		Timestamp: 1000,
+	}
+	require.NoError(SetManifest(ctx, store, manifestID, record))
+
+	stored, exists, err := GetManifest(ctx, store, manifestID)
+	require.NoError(err)
+	require.True(exists)
+	require.Equal(record, stored)
+
+	v, err := store.GetValue(ctx, ManifestKey(manifestID))
+	require.NoError(err)
+	require.Len(v, maxManifestRecordSize)
+	require.Equal(int(ManifestChunks), len(v)/64+1)
+
+	record.Blobs = append(record.Blobs, ids.GenerateTestID())
+	require.ErrorIs(SetManifest(ctx, store, manifestID, record), ErrInvalidManifestRecord)
+}
+
+func TestManifestRoot(t *testing.T) {
+	require := require.New(t)
+
+	a, b, c := ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()
+	require.Equal(ids.Empty, ManifestRoot(nil))
+	require.NotEqual(a, ManifestRoot([]ids.ID{a}))
+	require.Equal(ManifestRoot([]ids.ID{a, b, c}), ManifestRoot([]ids.ID{a, b, c}))
+	require.NotEqual(ManifestRoot([]ids.ID{a, b, c}), ManifestRoot([]ids.ID{b, a, c}))
+	require.NotEqual(ManifestRoot([]ids.ID{a, b}), ManifestRoot([]ids.ID{a, b, c}))
+
+	// The input is left untouched.
+	blobs := []ids.ID{a, b, c}
+	ManifestRoot(blobs)
+	require.Equal([]ids.ID{a, b, c}, blobs)
+}
@@ -28,10 +28,23 @@ type ReadState func(context.Context, [][]byte) ([][]byte, []error)
 //
 // 0x3/ (balance)
 //   -> [owner] => balance
+// 0x4/ (blob)
+//   -> [blobID] => blob record
+// 0x5/ (manifest)
+//   -> [manifestID] => manifest record
+
+const (
+	balancePrefix  byte = metadata.DefaultMinimumPrefix
+	blobPrefix          = balancePrefix + 1
+	manifestPrefix      = blobPrefix + 1
+)
 
-const balancePrefix byte = metadata.DefaultMinimumPrefix
-
-const BalanceChunks uint16 = 1
+const (
+	BalanceChunks uint16 = 1
+	BlobChunks    uint16 = 4
+	// ManifestChunks fits a record of [MaxManifestBlobs] blobs.
+	ManifestChunks uint16 = maxManifestRecordSize/64 + 1
+)
 
 // [balancePrefix] + [address]
 func BalanceKey(addr codec.Address) (k []byte) {
@@ -1,28 +1,58 @@
-package actions_test
+package tests
 
 import (
 	"context"
 	"testing"
 
+	"github.com/availproject/avail-go-sdk/src/sdk"
 	"github.com/stretchr/testify/assert"
+
 	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
 )
 
 func TestSendAvailAction(t *testing.T) {
-	seed := "SEED_PHRASE"
-	data := "Unfold24 CoinDCX"
-	networkURL := "AVAIL_URL"
-
+	node := datest.NewAvail(t)
 	action := actions.SendAvailAction{
-		Seed:       seed,
-		Data:       data,
-		NetworkURL: networkURL,
+		Seed:       "//Alice",
+		Data:       "Unfold24 CoinDCX",
+		NetworkURL: node.URL,
+		AppID:      1,
 	}
 
 	ctx := context.Background()
-	blockHash, txHash, err := action.Execute(ctx)
+	result, err := action.Execute(ctx)
+
+	assert.NoError(t, err, "Expected no error from Execute")
+	assert.NotEmpty(t, result.BlockHash, "Block hash should not be empty")
+	assert.NotEmpty(t, result.TxHash, "Transaction hash should not be empty")
+	assert.NotZero(t, result.BlockNumber, "Block number should be reported")
+	assert.Equal(t, uint32(0), result.ExtrinsicIndex, "Expected the only extrinsic of the block")
+	assert.False(t, result.Finalized, "Expected the block to be only included")
+
+	api, err := sdk.NewSDK(node.URL)
+	assert.NoError(t, err, "Expected to connect to the node")
+	defer api.Client.Close()
+	data, err := actions.RetrieveAvailData(ctx, api, result.BlockHash, result.ExtrinsicIndex, result.AppID)
+	assert.NoError(t, err, "Expected the data to be retrieved")
+	assert.Equal(t, []byte(action.Data), data)
+
+	_, err = actions.RetrieveAvailData(ctx, api, result.BlockHash, result.ExtrinsicIndex, result.AppID+1)
+	assert.ErrorIs(t, err, actions.ErrAvailAppIDMismatch)
+}
+
+func TestSendAvailActionAppKey(t *testing.T) {
+	node := datest.NewAvail(t)
+	action := actions.SendAvailAction{
+		Seed:       "//Alice",
+		Data:       "Unfold24 CoinDCX",
+		NetworkURL: node.URL,
+		AppKey:     "starter-kit",
+	}
 
+	result, err := action.Execute(context.Background())
 	assert.NoError(t, err, "Expected no error from Execute")
-	assert.NotEmpty(t, blockHash, "Block hash should not be empty")
-	assert.NotEmpty(t, txHash, "Transaction hash should not be empty")
+	appID, ok := node.AppID(action.AppKey)
+	assert.True(t, ok, "Expected the app key to be registered")
+	assert.Equal(t, appID, result.AppID)
 }
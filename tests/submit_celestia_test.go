@@ -1,23 +1,26 @@
-package actions_test
+package tests
 
 import (
 	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
 	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
 )
 
 func TestSendCelestiaAction(t *testing.T) {
+	node := datest.NewCelestia(t)
 	action := actions.SendCelestiaAction{
-		URL:       "TESTNET_NODE_URL", // You need to run a celestia local node
-		Token:     "TEST_TOKEN",                     // Replace with a test token
+		URL:       node.URL,
+		Token:     "TEST_TOKEN",
 		Namespace: []byte{0xDE, 0xAD, 0xBE, 0xEF},
-		Data:      []byte("Test Blob Data"),
+		Data:      "Test Blob Data",
 	}
 
 	ctx := context.Background()
-	result, err := action.Execute(ctx)
+	height, err := action.Execute(ctx)
 	assert.NoError(t, err, "Expected no error from Execute")
-	assert.Contains(t, result, "Blob successfully included at height", "Expected success message in result")
+	assert.Equal(t, int64(node.Height()), height, "Expected the inclusion height")
 }
@@ -2,21 +2,44 @@ package tests
 
 import (
 	"context"
+	"encoding/hex"
 	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
+
 	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
 )
 
 func TestSendEigenDAAction(t *testing.T) {
-	authKey := "EIGENDA_AUTH_PK"
-	sendAction := actions.NewSendEigenDAAction(authKey)
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate an auth key: %v", err)
+	}
+	authKey := hex.EncodeToString(crypto.FromECDSA(key))
+	disperser := datest.NewEigenDA(t)
+	sendAction, err := actions.NewSendEigenDAAction(authKey, disperser.DisperserConfig())
+	if err != nil {
+		t.Fatalf("Failed to create SendEigenDAAction: %v", err)
+	}
 
 	rawData := []byte("example data to disperse")
 
-	err := sendAction.Execute(context.Background(), rawData)
+	cert, err := sendAction.Execute(context.Background(), rawData)
 	if err != nil {
 		t.Fatalf("Failed to send data to EigenDA: %v", err)
 	}
 
 	assert.NoError(t, err, "SendEigenDAAction should not return an error")
+	assert.NotEmpty(t, cert.BatchHeaderHash, "certificate should locate the blob batch")
+
+	retriever, err := actions.NewEigenDARetriever(disperser.RetrieverConfig())
+	if err != nil {
+		t.Fatalf("Failed to create EigenDARetriever: %v", err)
+	}
+	defer retriever.Close()
+	data, err := retriever.RetrieveBlob(context.Background(), cert)
+	assert.NoError(t, err, "RetrieveBlob should not return an error")
+	assert.Equal(t, rawData, data[:len(rawData)])
 }
@@ -3,20 +3,30 @@ package tests
 import (
 	"context"
 	"testing"
-	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"time"
+
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/da/datest"
 )
 
 func TestSendBlobAction(t *testing.T) {
-	client, err := ethclient.Dial("RPC") 
+	node := datest.NewEthereum(t, 20*time.Millisecond)
+	client, err := ethclient.Dial(node.URL)
 	if err != nil {
 		t.Fatalf("Failed to connect to the Ethereum client: %v", err)
 	}
+	defer client.Close()
 
+	fees := actions.NewDefaultBlobFeeConfig()
+	fees.PollInterval = 10 * time.Millisecond
 	sendAction := actions.SendBlobAction{
-		Client:     client,
-		PrivateKey: "PRIV_KEY", // Replace with actual private key for testing
+		Client:        client,
+		PrivateKey:    node.PrivateKey,
+		Fees:          fees,
+		Confirmations: 1,
 	}
 	rawBlob := []byte("example raw blob data")
 	result, err := sendAction.Execute(context.Background(), rawBlob)
@@ -26,5 +36,7 @@ func TestSendBlobAction(t *testing.T) {
 
 	assert.Equal(t, "success", result.Status, "Expected status to be 'Success'")
 	assert.NotEmpty(t, result.TransactionHash, "Expected transaction hash to be non-empty")
+	assert.NotZero(t, result.BlockNumber, "Expected the inclusion block to be reported")
+	assert.NotZero(t, result.BlobGasUsed, "Expected blob gas to be reported")
 	assert.Equal(t, uint64(len(rawBlob)/1024), result.Units, "Expected the correct unit calculation")
 }
@@ -0,0 +1,288 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/codec"
+	"github.com/ava-labs/hypersdk/consts"
+	"github.com/ava-labs/hypersdk/event"
+)
+
+const (
+	DefaultBlobsLimit = 100
+	MaxBlobsLimit     = 1024
+)
+
+// Index layout
+// 0x0/ (submitter counter)
+//   -> [submitter] => next sequence number
+// 0x1/ (submitter)
+//   -> [submitter] + [sequence] => blobID
+// 0x2/ (namespace)
+//   -> [namespace length] + [namespace] + [height] + [blobID] => nil
+// 0x3/ (indexed range)
+//   -> [first height] + [last height] of the last run of consecutive blocks
+
+const (
+	submitterCounterPrefix byte = iota
+	submitterPrefix
+	namespacePrefix
+	indexedRangePrefix
+)
+
+var (
+	ErrCorruptIndex = errors.New("blob index is corrupt")
+
+	indexedRangeKey = []byte{indexedRangePrefix}
+)
+
+// IndexedRange is the range of chain heights the index holds every block
+// of.
+type IndexedRange struct {
+	FromHeight uint64 `json:"fromHeight"`
+	ToHeight   uint64 `json:"toHeight"`
+}
+
+// NamespaceCursor is the position of a blob in the namespace index.
+type NamespaceCursor struct {
+	Height uint64 `json:"height"`
+	BlobID ids.ID `json:"blobID"`
+}
+
+var _ event.Subscription[*chain.ExecutedBlock] = (*BlobIndex)(nil)
+
+// BlobIndex indexes the blobs registered in accepted blocks by submitter and
+// by namespace. The records themselves are always read from state.
+//
+// Only blocks accepted while the index is running are indexed: blocks
+// accepted while the node was down or state syncing are missing, and the
+// index is not backfilled since the VM does not expose past blocks.
+// [BlobIndex.IndexedRange] reports the heights the index is complete for.
+type BlobIndex struct {
+	// Accept is called sequentially, [mu] only guards [db] against queries
+	// racing with [Close].
+	mu sync.RWMutex
+	db database.Database
+}
+
+func NewBlobIndex(db database.Database) *BlobIndex {
+	return &BlobIndex{db: db}
+}
+
+func (i *BlobIndex) Accept(blk *chain.ExecutedBlock) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	batch := i.db.NewBatch()
+	counters := map[codec.Address]uint64{}
+	for j, tx := range blk.Block.Txs {
+		if !blk.Results[j].Success {
+			continue
+		}
+		submitter := tx.Auth.Actor()
+		for _, action := range tx.Actions {
+			register, ok := action.(*actions.RegisterBlobCommitment)
+			if !ok {
+				continue
+			}
+			seq, ok := counters[submitter]
+			if !ok {
+				var err error
+				seq, err = i.nextSeq(submitter)
+				if err != nil {
+					return err
+				}
+			}
+			if err := i.add(batch, submitter, seq, register); err != nil {
+				return err
+			}
+			counters[submitter] = seq + 1
+		}
+	}
+	for submitter, seq := range counters {
+		if err := batch.Put(submitterCounterKey(submitter), binary.BigEndian.AppendUint64(nil, seq)); err != nil {
+			return err
+		}
+	}
+	indexed, ok, err := i.indexedRange()
+	if err != nil {
+		return err
+	}
+	height := blk.Block.Hght
+	if !ok || height != indexed.ToHeight+1 {
+		// A gap starts a new run.
+		indexed.FromHeight = height
+	}
+	indexed.ToHeight = height
+	v := binary.BigEndian.AppendUint64(nil, indexed.FromHeight)
+	if err := batch.Put(indexedRangeKey, binary.BigEndian.AppendUint64(v, indexed.ToHeight)); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// IndexedRange returns the heights of the last run of consecutive blocks the
+// index accepted. Results for blocks before it may be incomplete. The second
+// return value is false if no block was indexed yet.
+func (i *BlobIndex) IndexedRange() (IndexedRange, bool, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.indexedRange()
+}
+
+func (i *BlobIndex) indexedRange() (IndexedRange, bool, error) {
+	v, err := i.db.Get(indexedRangeKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return IndexedRange{}, false, nil
+	}
+	if err != nil {
+		return IndexedRange{}, false, err
+	}
+	if len(v) != 2*consts.Uint64Len {
+		return IndexedRange{}, false, ErrCorruptIndex
+	}
+	return IndexedRange{
+		FromHeight: binary.BigEndian.Uint64(v),
+		ToHeight:   binary.BigEndian.Uint64(v[consts.Uint64Len:]),
+	}, true, nil
+}
+
+func (i *BlobIndex) Close() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.db.Close()
+}
+
+func (i *BlobIndex) add(
+	batch database.Batch,
+	submitter codec.Address,
+	seq uint64,
+	register *actions.RegisterBlobCommitment,
+) error {
+	blobID := storage.BlobID(register.Layer, register.Commitment)
+	if err := batch.Put(submitterKey(submitter, seq), blobID[:]); err != nil {
+		return err
+	}
+	if len(register.Namespace) == 0 {
+		return nil
+	}
+	return batch.Put(namespaceKey(register.Namespace, register.Height, blobID), nil)
+}
+
+func (i *BlobIndex) nextSeq(submitter codec.Address) (uint64, error) {
+	v, err := i.db.Get(submitterCounterKey(submitter))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return database.ParseUInt64(v)
+}
+
+// BySubmitter returns up to [limit] blobs registered by [submitter], in
+// registration order, starting at [cursor]. The returned cursor continues the
+// iteration.
+func (i *BlobIndex) BySubmitter(submitter codec.Address, cursor uint64, limit int) ([]ids.ID, uint64, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	prefix := make([]byte, 1+codec.AddressLen)
+	prefix[0] = submitterPrefix
+	copy(prefix[1:], submitter[:])
+	iter := i.db.NewIteratorWithStartAndPrefix(submitterKey(submitter, cursor), prefix)
+	defer iter.Release()
+
+	blobIDs := []ids.ID{}
+	for len(blobIDs) < limit && iter.Next() {
+		blobID, err := ids.ToID(iter.Value())
+		if err != nil {
+			return nil, 0, err
+		}
+		blobIDs = append(blobIDs, blobID)
+		cursor = binary.BigEndian.Uint64(iter.Key()[len(prefix):]) + 1
+	}
+	return blobIDs, cursor, iter.Error()
+}
+
+// ByNamespace returns up to [limit] blobs posted under [namespace] between DA
+// heights [fromHeight] and [toHeight] (inclusive), ordered by height and
+// blob ID. The iteration starts at [cursor] if it is not nil. The returned
+// cursor is the position of the next blob, nil once the range is exhausted.
+func (i *BlobIndex) ByNamespace(
+	namespace []byte,
+	fromHeight uint64,
+	toHeight uint64,
+	cursor *NamespaceCursor,
+	limit int,
+) ([]ids.ID, *NamespaceCursor, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	prefix := namespacePrefixKey(namespace)
+	start := binary.BigEndian.AppendUint64(prefix, fromHeight)
+	if cursor != nil && cursor.Height >= fromHeight {
+		start = namespaceKey(namespace, cursor.Height, cursor.BlobID)
+	}
+	iter := i.db.NewIteratorWithStartAndPrefix(start, prefix)
+	defer iter.Release()
+
+	blobIDs := []ids.ID{}
+	for iter.Next() {
+		key := iter.Key()[len(prefix):]
+		height := binary.BigEndian.Uint64(key)
+		if height > toHeight {
+			break
+		}
+		blobID, err := ids.ToID(key[consts.Uint64Len:])
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(blobIDs) == limit {
+			return blobIDs, &NamespaceCursor{Height: height, BlobID: blobID}, iter.Error()
+		}
+		blobIDs = append(blobIDs, blobID)
+	}
+	return blobIDs, nil, iter.Error()
+}
+
+func submitterCounterKey(submitter codec.Address) []byte {
+	k := make([]byte, 1+codec.AddressLen)
+	k[0] = submitterCounterPrefix
+	copy(k[1:], submitter[:])
+	return k
+}
+
+func submitterKey(submitter codec.Address, seq uint64) []byte {
+	k := make([]byte, 1+codec.AddressLen+consts.Uint64Len)
+	k[0] = submitterPrefix
+	copy(k[1:], submitter[:])
+	binary.BigEndian.PutUint64(k[1+codec.AddressLen:], seq)
+	return k
+}
+
+func namespacePrefixKey(namespace []byte) []byte {
+	k := make([]byte, 2+len(namespace), 2+len(namespace)+consts.Uint64Len+ids.IDLen)
+	k[0] = namespacePrefix
+	k[1] = byte(len(namespace))
+	copy(k[2:], namespace)
+	return k
+}
+
+func namespaceKey(namespace []byte, height uint64, blobID ids.ID) []byte {
+	k := binary.BigEndian.AppendUint64(namespacePrefixKey(namespace), height)
+	return append(k, blobID[:]...)
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
+	"github.com/ava-labs/hypersdk/auth"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/crypto/ed25519"
+)
+
+func newRegisterTx(pk ed25519.PrivateKey, registers ...*actions.RegisterBlobCommitment) *chain.Transaction {
+	acts := make(chain.Actions, 0, len(registers))
+	for _, register := range registers {
+		acts = append(acts, register)
+	}
+	return &chain.Transaction{
+		TransactionData: chain.TransactionData{Actions: acts},
+		Auth:            &auth.ED25519{Signer: pk.PublicKey()},
+	}
+}
+
+func TestBlobIndex(t *testing.T) {
+	require := require.New(t)
+
+	alice, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	bob, err := ed25519.GeneratePrivateKey()
+	require.NoError(err)
+	aliceAddr := auth.NewED25519Address(alice.PublicKey())
+	namespace := []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+	register := func(commitment byte, height uint64) *actions.RegisterBlobCommitment {
+		return &actions.RegisterBlobCommitment{
+			Layer:      consts.CelestiaLayerID,
+			Commitment: []byte{commitment},
+			Height:     height,
+			Namespace:  namespace,
+			Size:       1,
+		}
+	}
+	blobID := func(commitment byte) ids.ID {
+		return storage.BlobID(consts.CelestiaLayerID, []byte{commitment})
+	}
+
+	index := NewBlobIndex(memdb.New())
+	_, ok, err := index.IndexedRange()
+	require.NoError(err)
+	require.False(ok)
+	require.NoError(index.Accept(&chain.ExecutedBlock{
+		Block: &chain.StatelessBlock{Hght: 1, Txs: []*chain.Transaction{
+			newRegisterTx(alice, register(1, 10), register(2, 20)),
+			newRegisterTx(bob, register(3, 15)),
+			newRegisterTx(alice, register(4, 30)),
+		}},
+		Results: []*chain.Result{{Success: true}, {Success: true}, {Success: false}},
+	}))
+	require.NoError(index.Accept(&chain.ExecutedBlock{
+		Block:   &chain.StatelessBlock{Hght: 2, Txs: []*chain.Transaction{newRegisterTx(alice, register(5, 40))}},
+		Results: []*chain.Result{{Success: true}},
+	}))
+
+	blobIDs, cursor, err := index.BySubmitter(aliceAddr, 0, 2)
+	require.NoError(err)
+	require.Equal([]ids.ID{blobID(1), blobID(2)}, blobIDs)
+
+	blobIDs, cursor, err = index.BySubmitter(aliceAddr, cursor, 2)
+	require.NoError(err)
+	require.Equal([]ids.ID{blobID(5)}, blobIDs)
+
+	blobIDs, _, err = index.BySubmitter(aliceAddr, cursor, 2)
+	require.NoError(err)
+	require.Empty(blobIDs)
+
+	blobIDs, next, err := index.ByNamespace(namespace, 15, 40, nil, MaxBlobsLimit)
+	require.NoError(err)
+	require.Equal([]ids.ID{blobID(3), blobID(2), blobID(5)}, blobIDs)
+	require.Nil(next)
+
+	blobIDs, next, err = index.ByNamespace(namespace, 15, 40, nil, 2)
+	require.NoError(err)
+	require.Equal([]ids.ID{blobID(3), blobID(2)}, blobIDs)
+	require.Equal(&NamespaceCursor{Height: 40, BlobID: blobID(5)}, next)
+
+	blobIDs, next, err = index.ByNamespace(namespace, 15, 40, next, 2)
+	require.NoError(err)
+	require.Equal([]ids.ID{blobID(5)}, blobIDs)
+	require.Nil(next)
+
+	blobIDs, _, err = index.ByNamespace([]byte{0x01}, 0, 100, nil, MaxBlobsLimit)
+	require.NoError(err)
+	require.Empty(blobIDs)
+
+	indexed, ok, err := index.IndexedRange()
+	require.NoError(err)
+	require.True(ok)
+	require.Equal(IndexedRange{FromHeight: 1, ToHeight: 2}, indexed)
+
+	// Blocks accepted while the index was not running leave a gap.
+	require.NoError(index.Accept(&chain.ExecutedBlock{Block: &chain.StatelessBlock{Hght: 5}}))
+	indexed, _, err = index.IndexedRange()
+	require.NoError(err)
+	require.Equal(IndexedRange{FromHeight: 5, ToHeight: 5}, indexed)
+
+	require.NoError(index.Close())
+}
@@ -9,7 +9,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
+
 	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/da"
+	"github.com/ava-labs/hypersdk-starter-kit/storage"
 	"github.com/ava-labs/hypersdk/api/jsonrpc"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
@@ -65,6 +69,100 @@ func (cli *JSONRPCClient) Balance(ctx context.Context, addr codec.Address) (uint
 	return resp.Amount, err
 }
 
+// Blob returns the record of [blobID]. The returned error is
+// [ErrBlobNotFound] if the blob is not registered.
+func (cli *JSONRPCClient) Blob(ctx context.Context, blobID ids.ID) (*storage.BlobRecord, error) {
+	resp := new(BlobReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"blob",
+		&BlobArgs{
+			BlobID: blobID,
+		},
+		resp,
+	)
+	// Errors are received as plain strings.
+	if err != nil && strings.Contains(err.Error(), ErrBlobNotFound.Error()) {
+		return nil, ErrBlobNotFound
+	}
+	return resp.Blob, err
+}
+
+// Manifest returns the record of [manifestID]. The returned error is
+// [ErrManifestNotFound] if the manifest is not registered.
+func (cli *JSONRPCClient) Manifest(ctx context.Context, manifestID ids.ID) (*storage.ManifestRecord, error) {
+	resp := new(ManifestReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"manifest",
+		&ManifestArgs{
+			ManifestID: manifestID,
+		},
+		resp,
+	)
+	if err != nil && strings.Contains(err.Error(), ErrManifestNotFound.Error()) {
+		return nil, ErrManifestNotFound
+	}
+	return resp.Manifest, err
+}
+
+func (cli *JSONRPCClient) BlobsBySubmitter(
+	ctx context.Context,
+	addr codec.Address,
+	cursor uint64,
+	limit int,
+) ([]*BlobReply, uint64, error) {
+	resp := new(BlobsReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"blobsBySubmitter",
+		&BlobsBySubmitterArgs{
+			Address: addr,
+			Cursor:  cursor,
+			Limit:   limit,
+		},
+		resp,
+	)
+	return resp.Blobs, resp.NextCursor, err
+}
+
+func (cli *JSONRPCClient) BlobsByNamespace(
+	ctx context.Context,
+	namespace []byte,
+	fromHeight uint64,
+	toHeight uint64,
+	cursor *NamespaceCursor,
+	limit int,
+) ([]*BlobReply, *NamespaceCursor, error) {
+	resp := new(BlobsByNamespaceReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"blobsByNamespace",
+		&BlobsByNamespaceArgs{
+			Namespace:  namespace,
+			FromHeight: fromHeight,
+			ToHeight:   toHeight,
+			Cursor:     cursor,
+			Limit:      limit,
+		},
+		resp,
+	)
+	return resp.Blobs, resp.NextCursor, err
+}
+
+func (cli *JSONRPCClient) CostQuotes(ctx context.Context, size uint64) ([]*da.CostQuote, error) {
+	resp := new(CostQuotesReply)
+	err := cli.requester.SendRequest(
+		ctx,
+		"costQuotes",
+		&CostQuotesArgs{
+			Size: size,
+		},
+		resp,
+	)
+	return resp.Quotes, err
+}
+
 func (cli *JSONRPCClient) WaitForBalance(
 	ctx context.Context,
 	addr codec.Address,
@@ -0,0 +1,57 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+var (
+	_ genesis.GenesisAndRuleFactory = (*GenesisFactory)(nil)
+	_ chain.Rules                   = (*Rules)(nil)
+)
+
+// GenesisFactory loads a [genesis.DefaultGenesis] and the optional
+// "daFees" field of the same genesis file.
+type GenesisFactory struct{}
+
+func (GenesisFactory) Load(genesisBytes []byte, upgradeBytes []byte, networkID uint32, chainID ids.ID) (genesis.Genesis, genesis.RuleFactory, error) {
+	g, ruleFactory, err := genesis.DefaultGenesisFactory{}.Load(genesisBytes, upgradeBytes, networkID, chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var daGenesis struct {
+		DAFees *actions.DAFees `json:"daFees"`
+	}
+	if err := json.Unmarshal(genesisBytes, &daGenesis); err != nil {
+		return nil, nil, err
+	}
+	rules := &Rules{
+		Rules:  ruleFactory.GetRules(0).(*genesis.Rules),
+		DAFees: actions.NewDefaultDAFees(),
+	}
+	if daGenesis.DAFees != nil {
+		rules.DAFees = *daGenesis.DAFees
+	}
+	return g, &genesis.ImmutableRuleFactory{Rules: rules}, nil
+}
+
+// Rules extends [genesis.Rules] with the DA fee parameters.
+type Rules struct {
+	*genesis.Rules
+	DAFees actions.DAFees
+}
+
+func (r *Rules) FetchCustom(key string) (any, bool) {
+	if key == actions.DAFeesKey {
+		return r.DAFees, true
+	}
+	return r.Rules.FetchCustom(key)
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2024, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
+	"github.com/ava-labs/hypersdk/genesis"
+)
+
+func TestGenesisFactoryDAFees(t *testing.T) {
+	require := require.New(t)
+
+	genesisBytes, err := json.Marshal(genesis.NewDefaultGenesis(nil))
+	require.NoError(err)
+	_, ruleFactory, err := GenesisFactory{}.Load(genesisBytes, nil, 0, ids.Empty)
+	require.NoError(err)
+	require.Equal(actions.NewDefaultDAFees(), actions.GetDAFees(ruleFactory.GetRules(0)))
+
+	fees := actions.NewDefaultDAFees()
+	fees.Celestia = actions.DALayerFee{BaseFee: 1, FeePerByte: 2}
+	genesisBytes, err = json.Marshal(struct {
+		*genesis.DefaultGenesis
+		DAFees actions.DAFees `json:"daFees"`
+	}{genesis.NewDefaultGenesis(nil), fees})
+	require.NoError(err)
+	_, ruleFactory, err = GenesisFactory{}.Load(genesisBytes, nil, 0, ids.Empty)
+	require.NoError(err)
+	require.Equal(fees, actions.GetDAFees(ruleFactory.GetRules(0)))
+}
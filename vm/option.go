@@ -4,19 +4,43 @@
 package vm
 
 import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/pebbledb"
+
+	"github.com/ava-labs/hypersdk-starter-kit/da"
 	"github.com/ava-labs/hypersdk/api"
+	"github.com/ava-labs/hypersdk/chain"
+	"github.com/ava-labs/hypersdk/event"
 	"github.com/ava-labs/hypersdk/vm"
 )
 
-const Namespace = "controller"
+const (
+	Namespace = "controller"
+
+	blobIndexDir = "blobs"
+
+	// costOracleTimeout bounds connecting to the DA layers quoted by the
+	// costQuotes API.
+	costOracleTimeout = 10 * time.Second
+)
+
+var _ event.SubscriptionFactory[*chain.ExecutedBlock] = (*blobIndexFactory)(nil)
 
 type Config struct {
 	Enabled bool `json:"enabled"`
+	// DA configures the layers quoted by the costQuotes API. The API is
+	// disabled if DA.Costs.Layers is empty. Quotes only read public fee data,
+	// so no DA credentials are needed.
+	DA da.Config `json:"da"`
 }
 
 func NewDefaultConfig() Config {
 	return Config{
 		Enabled: true,
+		DA:      da.NewDefaultConfig(),
 	}
 }
 
@@ -25,6 +49,31 @@ func With() vm.Option {
 		if !config.Enabled {
 			return vm.NewOpt(), nil
 		}
-		return vm.WithVMAPIs(jsonRPCServerFactory{}), nil
+		db, err := pebbledb.New(filepath.Join(v.GetDataDir(), blobIndexDir), nil, v.Logger(), nil)
+		if err != nil {
+			return nil, err
+		}
+		index := NewBlobIndex(db)
+		var costs *da.CostOracle
+		if len(config.DA.Costs.Layers) > 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), costOracleTimeout)
+			costs, err = da.NewCostOracleFromConfig(ctx, config.DA)
+			cancel()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return vm.NewOpt(
+			vm.WithBlockSubscriptions(&blobIndexFactory{index: index}),
+			vm.WithVMAPIs(jsonRPCServerFactory{index: index, costs: costs}),
+		), nil
 	})
 }
+
+type blobIndexFactory struct {
+	index *BlobIndex
+}
+
+func (b *blobIndexFactory) New() (event.Subscription[*chain.ExecutedBlock], error) {
+	return b.index, nil
+}
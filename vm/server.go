@@ -4,23 +4,47 @@
 package vm
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/hypersdk-starter-kit/actions"
 	"github.com/ava-labs/hypersdk-starter-kit/consts"
+	"github.com/ava-labs/hypersdk-starter-kit/da"
 	"github.com/ava-labs/hypersdk-starter-kit/storage"
 	"github.com/ava-labs/hypersdk/api"
 	"github.com/ava-labs/hypersdk/codec"
 	"github.com/ava-labs/hypersdk/genesis"
 )
 
-const JSONRPCEndpoint = "/morpheusapi"
+const (
+	JSONRPCEndpoint = "/morpheusapi"
+
+	// costQuoteTimeout bounds the time spent pricing a payload on the DA
+	// layers.
+	costQuoteTimeout = 5 * time.Second
+)
+
+var (
+	ErrBlobNotFound       = errors.New("blob not found")
+	ErrManifestNotFound   = errors.New("manifest not found")
+	ErrCostOracleDisabled = errors.New("no DA layers are configured for cost quotes")
+	ErrEmptyPayload       = errors.New("payload size is zero")
+	ErrPayloadTooLarge    = errors.New("payload size is above the max blob size")
+)
 
 var _ api.HandlerFactory[api.VM] = (*jsonRPCServerFactory)(nil)
 
-type jsonRPCServerFactory struct{}
+type jsonRPCServerFactory struct {
+	index *BlobIndex
+	costs *da.CostOracle
+}
 
-func (jsonRPCServerFactory) New(vm api.VM) (api.Handler, error) {
-	handler, err := api.NewJSONRPCHandler(consts.Name, NewJSONRPCServer(vm))
+func (f jsonRPCServerFactory) New(vm api.VM) (api.Handler, error) {
+	handler, err := api.NewJSONRPCHandler(consts.Name, NewJSONRPCServer(vm, f.index, f.costs))
 	return api.Handler{
 		Path:    JSONRPCEndpoint,
 		Handler: handler,
@@ -28,11 +52,15 @@ func (jsonRPCServerFactory) New(vm api.VM) (api.Handler, error) {
 }
 
 type JSONRPCServer struct {
-	vm api.VM
+	vm    api.VM
+	index *BlobIndex
+	costs *da.CostOracle
 }
 
-func NewJSONRPCServer(vm api.VM) *JSONRPCServer {
-	return &JSONRPCServer{vm: vm}
+// NewJSONRPCServer returns the MorpheusVM API. [costs] may be nil, in which
+// case CostQuotes is disabled.
+func NewJSONRPCServer(vm api.VM, index *BlobIndex, costs *da.CostOracle) *JSONRPCServer {
+	return &JSONRPCServer{vm: vm, index: index, costs: costs}
 }
 
 type GenesisReply struct {
@@ -63,3 +91,186 @@ func (j *JSONRPCServer) Balance(req *http.Request, args *BalanceArgs, reply *Bal
 	reply.Amount = balance
 	return err
 }
+
+type BlobArgs struct {
+	BlobID ids.ID `json:"blobID"`
+}
+
+type BlobReply struct {
+	BlobID ids.ID              `json:"blobID"`
+	Blob   *storage.BlobRecord `json:"blob"`
+}
+
+func (j *JSONRPCServer) Blob(req *http.Request, args *BlobArgs, reply *BlobReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Blob")
+	defer span.End()
+
+	record, exists, err := storage.GetBlobFromState(ctx, j.vm.ReadState, args.BlobID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrBlobNotFound
+	}
+	reply.BlobID = args.BlobID
+	reply.Blob = record
+	return nil
+}
+
+type ManifestArgs struct {
+	ManifestID ids.ID `json:"manifestID"`
+}
+
+type ManifestReply struct {
+	ManifestID ids.ID                  `json:"manifestID"`
+	Manifest   *storage.ManifestRecord `json:"manifest"`
+}
+
+func (j *JSONRPCServer) Manifest(req *http.Request, args *ManifestArgs, reply *ManifestReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.Manifest")
+	defer span.End()
+
+	record, exists, err := storage.GetManifestFromState(ctx, j.vm.ReadState, args.ManifestID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrManifestNotFound
+	}
+	reply.ManifestID = args.ManifestID
+	reply.Manifest = record
+	return nil
+}
+
+type BlobsBySubmitterArgs struct {
+	Address codec.Address `json:"address"`
+	Cursor  uint64        `json:"cursor"`
+	Limit   int           `json:"limit"`
+}
+
+type BlobsReply struct {
+	Blobs      []*BlobReply `json:"blobs"`
+	NextCursor uint64       `json:"nextCursor"`
+	// Indexed is the range of chain heights the index is complete for, blobs
+	// registered before it may be missing. It is nil if no block was indexed.
+	Indexed *IndexedRange `json:"indexed,omitempty"`
+}
+
+func (j *JSONRPCServer) BlobsBySubmitter(req *http.Request, args *BlobsBySubmitterArgs, reply *BlobsReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.BlobsBySubmitter")
+	defer span.End()
+
+	blobIDs, nextCursor, err := j.index.BySubmitter(args.Address, args.Cursor, blobsLimit(args.Limit))
+	if err != nil {
+		return err
+	}
+	blobs, err := j.readBlobs(ctx, blobIDs)
+	if err != nil {
+		return err
+	}
+	reply.Blobs = blobs
+	reply.NextCursor = nextCursor
+	reply.Indexed, err = j.indexedRange()
+	return err
+}
+
+type BlobsByNamespaceArgs struct {
+	Namespace  codec.Bytes      `json:"namespace"`
+	FromHeight uint64           `json:"fromHeight"`
+	ToHeight   uint64           `json:"toHeight"`
+	Cursor     *NamespaceCursor `json:"cursor,omitempty"`
+	Limit      int              `json:"limit"`
+}
+
+type BlobsByNamespaceReply struct {
+	Blobs []*BlobReply `json:"blobs"`
+	// NextCursor continues the iteration, it is nil once the range is
+	// exhausted.
+	NextCursor *NamespaceCursor `json:"nextCursor,omitempty"`
+	Indexed    *IndexedRange    `json:"indexed,omitempty"`
+}
+
+func (j *JSONRPCServer) BlobsByNamespace(req *http.Request, args *BlobsByNamespaceArgs, reply *BlobsByNamespaceReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.BlobsByNamespace")
+	defer span.End()
+
+	if len(args.Namespace) > storage.MaxNamespaceSize {
+		return actions.ErrNamespaceTooLarge
+	}
+	blobIDs, nextCursor, err := j.index.ByNamespace(args.Namespace, args.FromHeight, args.ToHeight, args.Cursor, blobsLimit(args.Limit))
+	if err != nil {
+		return err
+	}
+	blobs, err := j.readBlobs(ctx, blobIDs)
+	if err != nil {
+		return err
+	}
+	reply.Blobs = blobs
+	reply.NextCursor = nextCursor
+	reply.Indexed, err = j.indexedRange()
+	return err
+}
+
+func (j *JSONRPCServer) indexedRange() (*IndexedRange, error) {
+	indexed, ok, err := j.index.IndexedRange()
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &indexed, nil
+}
+
+type CostQuotesArgs struct {
+	Size uint64 `json:"size"`
+}
+
+type CostQuotesReply struct {
+	Quotes []*da.CostQuote `json:"quotes"`
+}
+
+// CostQuotes quotes the cost of posting a payload of [args.Size] bytes to
+// every configured DA layer. A layer not answering within [costQuoteTimeout]
+// is reported with an error.
+func (j *JSONRPCServer) CostQuotes(req *http.Request, args *CostQuotesArgs, reply *CostQuotesReply) error {
+	ctx, span := j.vm.Tracer().Start(req.Context(), "Server.CostQuotes")
+	defer span.End()
+
+	if j.costs == nil {
+		return ErrCostOracleDisabled
+	}
+	if args.Size == 0 {
+		return ErrEmptyPayload
+	}
+	if args.Size > actions.MaxBlobSize {
+		return ErrPayloadTooLarge
+	}
+	ctx, cancel := context.WithTimeout(ctx, costQuoteTimeout)
+	defer cancel()
+	reply.Quotes = j.costs.Quote(ctx, args.Size)
+	return nil
+}
+
+func (j *JSONRPCServer) readBlobs(ctx context.Context, blobIDs []ids.ID) ([]*BlobReply, error) {
+	blobs := make([]*BlobReply, 0, len(blobIDs))
+	for _, blobID := range blobIDs {
+		record, exists, err := storage.GetBlobFromState(ctx, j.vm.ReadState, blobID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		blobs = append(blobs, &BlobReply{BlobID: blobID, Blob: record})
+	}
+	return blobs, nil
+}
+
+func blobsLimit(limit int) int {
+	switch {
+	case limit <= 0:
+		return DefaultBlobsLimit
+	case limit > MaxBlobsLimit:
+		return MaxBlobsLimit
+	default:
+		return limit
+	}
+}
@@ -12,7 +12,6 @@ import (
 	"github.com/ava-labs/hypersdk/auth"
 	"github.com/ava-labs/hypersdk/chain"
 	"github.com/ava-labs/hypersdk/codec"
-	"github.com/ava-labs/hypersdk/genesis"
 	"github.com/ava-labs/hypersdk/state/metadata"
 	"github.com/ava-labs/hypersdk/vm"
 	"github.com/ava-labs/hypersdk/vm/defaultvm"
@@ -41,10 +40,8 @@ func init() {
 		// When registering new actions, ALWAYS make sure to append at the end.
 		// Pass nil as second argument if manual marshalling isn't needed (if in doubt, you probably don't)
 		ActionParser.Register(&actions.Transfer{}, nil),
-		ActionParser.Register(&actions.SendBlobAction{}, nil),
-		ActionParser.Register(&actions.SendCelestiaAction{}, nil),
-		ActionParser.Register(&actions.SendAvailAction{}, nil),
-		ActionParser.Register(&actions.sendEigenDAAction{}, nil),
+		ActionParser.Register(&actions.RegisterBlobCommitment{}, nil),
+		ActionParser.Register(&actions.RegisterBlobManifest{}, nil),
 
 		// When registering new auth, ALWAYS make sure to append at the end.
 		AuthParser.Register(&auth.ED25519{}, auth.UnmarshalED25519),
@@ -52,8 +49,8 @@ func init() {
 		AuthParser.Register(&auth.BLS{}, auth.UnmarshalBLS),
 
 		OutputParser.Register(&actions.TransferResult{}, nil),
-		OutputParser.Register(&actions.SendBlobActionResult{}, nil),
-
+		OutputParser.Register(&actions.RegisterBlobCommitmentResult{}, nil),
+		OutputParser.Register(&actions.RegisterBlobManifestResult{}, nil),
 	)
 
 	if errs.Errored() {
@@ -66,7 +63,7 @@ func New(options ...vm.Option) (*vm.VM, error) {
 	options = append(options, With()) // Add MorpheusVM API
 	return defaultvm.New(
 		consts.Version,
-		genesis.DefaultGenesisFactory{},
+		GenesisFactory{},
 		&storage.BalanceHandler{},
 		metadata.NewDefaultManager(),
 		ActionParser,
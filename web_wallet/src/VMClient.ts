@@ -31,3 +31,34 @@ export async function isFaucetReady(): Promise<boolean> {
         return false;
     }
 }
+
+export type CostQuote = {
+    layer: string
+    size: number
+    token: { symbol: string, decimals: number }
+    fee?: string
+    cost?: number
+    usd?: number
+    error?: string
+}
+
+export async function getCostQuotes(size: number): Promise<CostQuote[]> {
+    const response = await fetch(`${API_HOST}/ext/bc/${VM_NAME}/${VM_RPC_PREFIX}`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+            jsonrpc: '2.0',
+            id: 1,
+            method: `${VM_NAME}.costQuotes`,
+            params: { size },
+        })
+    });
+    if (!response.ok) {
+        throw new Error(`HTTP error! status: ${response.status}`);
+    }
+    const json = await response.json();
+    if (json.error) {
+        throw new Error(json.error.message);
+    }
+    return json.result.quotes;
+}